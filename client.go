@@ -3,14 +3,35 @@ package iec61850
 // #include <iec61850_client.h>
 import "C"
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"sync/atomic"
 	"unsafe"
 )
 
+// Client IEC 61850 客户端，所有方法都可以在多个 goroutine 中并发调用。
+// Close 开始后新的调用返回 NotConnected，Close 等待进行中的调用结束后再释放连接，回调中不能调用 Close
 type Client struct {
 	conn      C.IedConnection
 	tlsConfig C.TLSConfiguration
-	connected *atomic.Bool
+	guard     callGuard
+	settings  Settings
+
+	acseAuth     C.AcseAuthenticationParameter
+	localAddress *C.char
+
+	state           atomic.Int32
+	stateCallbackId int32
+	stateHandler    atomic.Pointer[ConnectionStateHandler]
+	supervisor      *clientSupervisor
+
+	mu              sync.RWMutex
+	reportHandlers  map[string]*installedReportHandler
+	bufferedReports map[string]*BufferedReportSubscription
+	reportStreams   map[string]*reportStream
+	controlObjects  map[int32]*ControlObject
+	sgcbReferences  sync.Map // 逻辑设备名到 SGCB 引用的缓存
 }
 
 // Settings 连接配置
@@ -19,14 +40,36 @@ type Settings struct {
 	Port           int
 	ConnectTimeout uint // 连接超时配置，单位：毫秒
 	RequestTimeout uint // 请求超时配置，单位：毫秒
+	// MaxOutstandingCalls 单个连接上允许同时进行的异步请求数量，0 表示使用 libiec61850 默认值
+	MaxOutstandingCalls int
+	// FilestoreBasepath 客户端本地文件存储目录，上传文件时服务端从该目录读取
+	FilestoreBasepath string
+
+	// AcsePassword ACSE 密码认证，为空时不认证
+	AcsePassword string
+	// RemoteApTitle 服务端 AP title，如 1.1.1.999，为空时使用 libiec61850 默认值
+	RemoteApTitle     string
+	RemoteAeQualifier int
+	// LocalApTitle 客户端 AP title，如 1.1.1.999.1，为空时使用 libiec61850 默认值
+	LocalApTitle     string
+	LocalAeQualifier int
+	// RemoteSelectors 服务端 PSEL/SSEL/TSEL，为 nil 时使用 libiec61850 默认值
+	RemoteSelectors *IsoSelectors
+	// LocalSelectors 客户端 PSEL/SSEL/TSEL，为 nil 时使用 libiec61850 默认值
+	LocalSelectors *IsoSelectors
+	// LocalAddress 绑定的本地 IP 地址，为空时由系统选择
+	LocalAddress string
+	// LocalPort 绑定的本地端口，小于 1 时由系统选择
+	LocalPort int
 }
 
 func NewSettings() Settings {
 	return Settings{
-		Host:           "localhost",
-		Port:           102,
-		ConnectTimeout: 10000,
-		RequestTimeout: 10000,
+		Host:              "localhost",
+		Port:              102,
+		ConnectTimeout:    10000,
+		RequestTimeout:    10000,
+		FilestoreBasepath: DefaultFilestoreBasepath,
 	}
 }
 
@@ -44,49 +87,104 @@ func NewClient(settings Settings) (*Client, error) {
 }
 
 func newClient(settings Settings, tlsConfig *TLSConfig) (*Client, error) {
-	client := &Client{}
+	client, err := createClient(settings, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := client.connect(settings, tlsConfig); err != nil {
+	if err = client.connect(); err != nil {
+		client.destroy()
 		return nil, err
 	}
+	return client, nil
+}
+
+// createClient 创建客户端实例，但不建立连接
+func createClient(settings Settings, tlsConfig *TLSConfig) (*Client, error) {
+	client := &Client{
+		settings:        settings,
+		reportHandlers:  make(map[string]*installedReportHandler),
+		bufferedReports: make(map[string]*BufferedReportSubscription),
+		reportStreams:   make(map[string]*reportStream),
+		controlObjects:  make(map[int32]*ControlObject),
+	}
+
+	if tlsConfig != nil {
+		_tlsConfig, err := tlsConfig.createCTlsConfig()
+		if err != nil {
+			return nil, err
+		}
 
-	connected := &atomic.Bool{}
-	connected.Store(true)
-	client.connected = connected
+		client.tlsConfig = _tlsConfig
+		client.conn = C.IedConnection_createWithTlsSupport(_tlsConfig)
+	} else {
+		client.conn = C.IedConnection_create()
+	}
+
+	C.IedConnection_setConnectTimeout(client.conn, C.uint(settings.ConnectTimeout))
+	C.IedConnection_setRequestTimeout(client.conn, C.uint(settings.RequestTimeout))
+	if settings.MaxOutstandingCalls > 0 {
+		C.IedConnection_setMaxOutstandingCalls(client.conn, C.int(settings.MaxOutstandingCalls), C.int(settings.MaxOutstandingCalls))
+	}
+	if settings.FilestoreBasepath != "" {
+		cBasepath := C.CString(settings.FilestoreBasepath)
+		C.IedConnection_setFilestoreBasepath(client.conn, cBasepath)
+		C.free(unsafe.Pointer(cBasepath))
+	}
+	if err := client.applyIsoParameters(); err != nil {
+		C.IedConnection_destroy(client.conn)
+		client.destroyIsoParameters()
+		if client.tlsConfig != nil {
+			C.TLSConfiguration_destroy(client.tlsConfig)
+		}
+		return nil, err
+	}
+	client.installStateChangedHandler()
 	return client, nil
 }
 
-// Write 写单个属性值，不支持Structure
+// Write 写单个属性值。结构体可以传入按顺序排列的成员（[]*MmsValue、[]interface{}）或按成员名的 map[string]interface{}，
+// 数组传入切片；位串传入 BitStringValue、[]bool 或 Quality，八位组串传入 []byte，UtcTime 和 BinaryTime 传入 time.Time
 func (c *Client) Write(objectRef string, fc FC, value interface{}) error {
-	mmsType, err := c.GetVariableSpecType(objectRef, fc)
-	if err != nil {
+	if err := c.acquire(); err != nil {
 		return err
 	}
-	var (
-		mmsValue    *C.MmsValue
-		clientError C.IedClientError
-	)
+	defer c.release()
 
-	mmsValue, err = toMmsValue(mmsType, value)
-	if err != nil {
+	var clientError C.IedClientError
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	// 按类型规格构造值，结构体、数组、位串和字符串需要规格中的成员和长度信息
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("write", objectRef, fc, clientError); err != nil {
 		return err
 	}
+	defer C.MmsVariableSpecification_destroy(spec)
 
-	cObjectRef := C.CString(objectRef)
-	defer C.free(unsafe.Pointer(cObjectRef))
+	mmsValue, err := toMmsValueBySpec(spec, value)
+	if err != nil {
+		return err
+	}
 	defer C.MmsValue_delete(mmsValue)
+
 	C.IedConnection_writeObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc), mmsValue)
-	return GetIedClientError(clientError)
+	return newIedError("write", objectRef, fc, clientError)
 }
 
 // ReadBool 读取bool类型值
 func (c *Client) ReadBool(objectRef string, fc FC) (bool, error) {
+	if err := c.acquire(); err != nil {
+		return false, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readBooleanValue(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return false, err
 	}
 	return bool(value), nil
@@ -94,12 +192,17 @@ func (c *Client) ReadBool(objectRef string, fc FC) (bool, error) {
 
 // ReadInt32 读取int32类型值
 func (c *Client) ReadInt32(objectRef string, fc FC) (int32, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readInt32Value(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return 0, err
 	}
 	return int32(value), nil
@@ -107,12 +210,17 @@ func (c *Client) ReadInt32(objectRef string, fc FC) (int32, error) {
 
 // ReadInt64 读取int64类型值
 func (c *Client) ReadInt64(objectRef string, fc FC) (int64, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readInt64Value(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return 0, err
 	}
 	return int64(value), nil
@@ -120,12 +228,17 @@ func (c *Client) ReadInt64(objectRef string, fc FC) (int64, error) {
 
 // ReadUint32 读取uint32类型值
 func (c *Client) ReadUint32(objectRef string, fc FC) (uint32, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readUnsigned32Value(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return 0, err
 	}
 	return uint32(value), nil
@@ -133,12 +246,17 @@ func (c *Client) ReadUint32(objectRef string, fc FC) (uint32, error) {
 
 // ReadFloat 读取float类型值
 func (c *Client) ReadFloat(objectRef string, fc FC) (float32, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readFloatValue(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return 0, err
 	}
 	//源码返回值是C的float，4byte，所以应返回float32，否则会出现其他问题
@@ -147,12 +265,17 @@ func (c *Client) ReadFloat(objectRef string, fc FC) (float32, error) {
 
 // ReadString 读取string类型值
 func (c *Client) ReadString(objectRef string, fc FC) (string, error) {
+	if err := c.acquire(); err != nil {
+		return "", err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	value := C.IedConnection_readStringValue(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return "", err
 	}
 	return C.GoString(value), nil
@@ -160,12 +283,17 @@ func (c *Client) ReadString(objectRef string, fc FC) (string, error) {
 
 // Read 读取属性数据
 func (c *Client) Read(objectRef string, fc FC) (interface{}, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	mmsValue := C.IedConnection_readObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
 		return nil, err
 	}
 
@@ -176,16 +304,24 @@ func (c *Client) Read(objectRef string, fc FC) (interface{}, error) {
 
 // ReadDataSet 读取DataSet
 func (c *Client) ReadDataSet(objectRef string) ([]*MmsValue, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	var clientError C.IedClientError
 	dataSet := C.IedConnection_readDataSetValues(c.conn, &clientError, cObjectRef, nil)
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read data set", objectRef, NONE, clientError); err != nil {
 		return nil, err
 	}
 	defer C.ClientDataSet_destroy(dataSet)
+	return toDataSetValues(dataSet)
+}
 
+func toDataSetValues(dataSet C.ClientDataSet) ([]*MmsValue, error) {
 	dataSetValues := C.ClientDataSet_getValues(dataSet)
 	// 长度
 	dataSetSize := int(C.ClientDataSet_getDataSetSize(dataSet))
@@ -207,98 +343,133 @@ func (c *Client) ReadDataSet(objectRef string) ([]*MmsValue, error) {
 	return mmsValues, nil
 }
 
-// Close 关闭连接
+// Close 关闭连接，自动重连的客户端会先停止重连。
+// 关闭开始后新的调用返回 NotConnected，进行中的调用结束后才释放连接，可以重复调用
 func (c *Client) Close() {
-	if c.conn != nil && c.connected.CompareAndSwap(true, false) {
-		C.IedConnection_destroy(c.conn)
+	idle := c.guard.close()
+	if idle == nil {
+		return
+	}
+	if c.supervisor != nil {
+		c.supervisor.stop()
+	}
+	<-idle
+	c.destroy()
+}
 
-		if c.tlsConfig != nil {
-			C.TLSConfiguration_destroy(c.tlsConfig)
-		}
+// destroy 先注销报告和控制回调、释放控制对象，再释放连接
+func (c *Client) destroy() {
+	c.mu.Lock()
+	for _, installed := range c.reportHandlers {
+		reportCallbacks.Delete(installed.callbackId)
+	}
+	c.reportHandlers = make(map[string]*installedReportHandler)
+	c.bufferedReports = make(map[string]*BufferedReportSubscription)
+	streams := c.reportStreams
+	c.reportStreams = make(map[string]*reportStream)
+	controls := c.controlObjects
+	c.controlObjects = make(map[int32]*ControlObject)
+	c.mu.Unlock()
+
+	// ControlObjectClient_destroy 会访问连接，需要在连接释放前调用
+	for _, o := range controls {
+		o.Close()
+	}
+
+	C.IedConnection_destroy(c.conn)
+	connectionStateCallbacks.Delete(c.stateCallbackId)
+
+	for _, stream := range streams {
+		stream.close()
+	}
+
+	c.destroyIsoParameters()
+	if c.tlsConfig != nil {
+		C.TLSConfiguration_destroy(c.tlsConfig)
 	}
 }
 
 // GetVariableSpecType 获取类型规格
 func (c *Client) GetVariableSpecType(objectReference string, fc FC) (MmsType, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	// 获取类型
 	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("get variable specification", objectReference, fc, clientError); err != nil {
 		return 0, err
 	}
 	defer C.MmsVariableSpecification_destroy(spec)
+	return toSpecMmsType(spec), nil
+}
+
+// toSpecMmsType 根据类型规格获取类型，整数类型按位宽细分
+func toSpecMmsType(spec *C.MmsVariableSpecification) MmsType {
 	mmsType := MmsType(C.MmsVariableSpecification_getType(spec))
 	switch mmsType {
 	case Integer:
 		i := int(spec.typeSpec[0])
 		switch i {
 		case 8:
-			return Int8, nil
+			return Int8
 		case 16:
-			return Int16, nil
+			return Int16
 		case 32:
-			return Int32, nil
+			return Int32
 		default:
-			return Int64, nil
+			return Int64
 		}
 	case Unsigned:
 		switch int(spec.typeSpec[0]) {
 		case 8:
-			return Uint8, nil
+			return Uint8
 		case 16:
-			return Uint16, nil
+			return Uint16
 		default:
-			return Uint32, nil
+			return Uint32
 		}
 	default:
-		return mmsType, nil
+		return mmsType
 	}
 }
 
 func (c *Client) getSubElementValue(sgcbVal *C.MmsValue, sgcbVarSpec *C.MmsVariableSpecification, name string) (interface{}, error) {
+	mmsValue, err := c.getSubElement(sgcbVal, sgcbVarSpec, name)
+	if err != nil {
+		return nil, err
+	}
+	return toGoValue(mmsValue, MmsType(C.MmsValue_getType(mmsValue)))
+}
+
+// getSubElement 子元素属于 sgcbVal，随 sgcbVal 一起释放
+func (c *Client) getSubElement(sgcbVal *C.MmsValue, sgcbVarSpec *C.MmsVariableSpecification, name string) (*C.MmsValue, error) {
 	mmsPath := C.CString(name)
 	defer C.free(unsafe.Pointer(mmsPath))
 	mmsValue := C.MmsValue_getSubElement(sgcbVal, sgcbVarSpec, mmsPath)
-	defer C.MmsValue_delete(mmsValue)
-	return toGoValue(mmsValue, MmsType(C.MmsValue_getType(mmsValue)))
+	if mmsValue == nil {
+		return nil, fmt.Errorf("%w: %s", StructureComponentMissing, name)
+	}
+	return mmsValue, nil
 }
 
 // connect 建立连接
-func (c *Client) connect(settings Settings, tlsConfig *TLSConfig) error {
-	var conn C.IedConnection
-
-	if tlsConfig != nil {
-		_tlsConfig, err := tlsConfig.createCTlsConfig()
-		if err != nil {
-			return err
-		}
-
-		c.tlsConfig = _tlsConfig
-		conn = C.IedConnection_createWithTlsSupport(_tlsConfig)
-	} else {
-		conn = C.IedConnection_create()
-	}
-
-	C.IedConnection_setConnectTimeout(conn, C.uint(settings.ConnectTimeout))
-	C.IedConnection_setRequestTimeout(conn, C.uint(settings.RequestTimeout))
-	host := C.CString(settings.Host)
+func (c *Client) connect() error {
+	host := C.CString(c.settings.Host)
 	// 释放内存
 	defer C.free(unsafe.Pointer(host))
 
 	var clientError C.IedClientError
-	C.IedConnection_connect(conn, &clientError, host, C.int(settings.Port))
-
-	if err := GetIedClientError(clientError); err != nil {
-		if c.tlsConfig != nil {
-			C.TLSConfiguration_destroy(c.tlsConfig)
-		}
-		C.IedConnection_destroy(conn)
-		return err
-	}
+	C.IedConnection_connect(c.conn, &clientError, host, C.int(c.settings.Port))
+	return newIedError("connect", "", NONE, clientError)
+}
 
-	c.conn = conn
-	return nil
+// isNotConnected 判断错误是否由连接未建立或已断开引起
+func isNotConnected(err error) bool {
+	return errors.Is(err, NotConnected) || errors.Is(err, ConnectionLost)
 }
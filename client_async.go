@@ -0,0 +1,253 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+extern void genericServiceHandlerBridge(uint32_t invokeId, void* parameter, IedClientError err);
+
+extern void readObjectHandlerBridge(uint32_t invokeId, void* parameter, IedClientError err, MmsValue* value);
+
+extern void readDataSetHandlerBridge(uint32_t invokeId, void* parameter, IedClientError err, ClientDataSet dataSet);
+
+extern void getRCBValuesHandlerBridge(uint32_t invokeId, void* parameter, IedClientError err, ClientReportControlBlock rcb);
+
+extern void getVariableSpecificationHandlerBridge(uint32_t invokeId, void* parameter, IedClientError err, MmsVariableSpecification* spec);
+*/
+import "C"
+import (
+	"context"
+	"sync"
+	"unsafe"
+)
+
+// asyncCalls 保存等待响应的异步请求，key 为回调 id
+var asyncCalls sync.Map
+
+type asyncResult struct {
+	value interface{}
+	code  C.IedClientError // 服务错误码，由 await 转换为 IedError
+	err   error            // 响应值转换错误
+}
+
+// deliverAsyncResult 将响应交给等待中的请求，请求已被取消时丢弃结果
+func deliverAsyncResult(parameter unsafe.Pointer, value interface{}, code C.IedClientError, err error) {
+	callbackId := int32(uintptr(parameter))
+	if val, ok := asyncCalls.LoadAndDelete(callbackId); ok {
+		if result, ok := val.(chan asyncResult); ok {
+			result <- asyncResult{value: value, code: code, err: err}
+		}
+	}
+}
+
+//export genericServiceHandlerBridge
+func genericServiceHandlerBridge(_ C.uint32_t, parameter unsafe.Pointer, err C.IedClientError) {
+	deliverAsyncResult(parameter, nil, err, nil)
+}
+
+//export readObjectHandlerBridge
+func readObjectHandlerBridge(_ C.uint32_t, parameter unsafe.Pointer, err C.IedClientError, value *C.MmsValue) {
+	if value == nil {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	defer C.MmsValue_delete(value)
+
+	if err != C.IED_ERROR_OK {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	goValue, goErr := toGoValue(value, MmsType(C.MmsValue_getType(value)))
+	deliverAsyncResult(parameter, goValue, C.IED_ERROR_OK, goErr)
+}
+
+//export readDataSetHandlerBridge
+func readDataSetHandlerBridge(_ C.uint32_t, parameter unsafe.Pointer, err C.IedClientError, dataSet C.ClientDataSet) {
+	if dataSet == nil {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	defer C.ClientDataSet_destroy(dataSet)
+
+	if err != C.IED_ERROR_OK {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	values, goErr := toDataSetValues(dataSet)
+	deliverAsyncResult(parameter, values, C.IED_ERROR_OK, goErr)
+}
+
+//export getRCBValuesHandlerBridge
+func getRCBValuesHandlerBridge(_ C.uint32_t, parameter unsafe.Pointer, err C.IedClientError, rcb C.ClientReportControlBlock) {
+	if rcb == nil {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	defer C.ClientReportControlBlock_destroy(rcb)
+
+	if err != C.IED_ERROR_OK {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	deliverAsyncResult(parameter, toClientReportControlBlock(rcb), C.IED_ERROR_OK, nil)
+}
+
+//export getVariableSpecificationHandlerBridge
+func getVariableSpecificationHandlerBridge(_ C.uint32_t, parameter unsafe.Pointer, err C.IedClientError, spec *C.MmsVariableSpecification) {
+	if spec == nil {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	if err != C.IED_ERROR_OK {
+		deliverAsyncResult(parameter, nil, err, nil)
+		return
+	}
+	deliverAsyncResult(parameter, toSpecMmsType(spec), C.IED_ERROR_OK, nil)
+}
+
+// await 发起异步请求并等待响应，服务失败时返回带 op、objectRef 和 fc 的 IedError。ctx 结束时立即返回 ctx.Err()，
+// 已发出的请求仍由 libiec61850 在收到响应或 RequestTimeout 到期后释放
+func await(ctx context.Context, op, objectRef string, fc FC, send func(callbackId int32) C.IedClientError) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	result := make(chan asyncResult, 1)
+	asyncCalls.Store(callbackId, result)
+
+	if err := newIedError(op, objectRef, fc, send(callbackId)); err != nil {
+		asyncCalls.Delete(callbackId)
+		return nil, err
+	}
+
+	select {
+	case r := <-result:
+		if err := newIedError(op, objectRef, fc, r.code); err != nil {
+			return nil, err
+		}
+		return r.value, r.err
+	case <-ctx.Done():
+		asyncCalls.Delete(callbackId)
+		return nil, ctx.Err()
+	}
+}
+
+// ReadContext 读取属性数据，ctx 取消时放弃等待
+func (c *Client) ReadContext(ctx context.Context, objectRef string, fc FC) (interface{}, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	return await(ctx, "read", objectRef, fc, func(callbackId int32) C.IedClientError {
+		var clientError C.IedClientError
+		// intToPointerBug58625 must be inlined at the C call: storing the fake unsafe.Pointer in a local would let Go 1.26's stack scanner reject it.
+		C.IedConnection_readObjectAsync(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc), (*[0]byte)(C.readObjectHandlerBridge), intToPointerBug58625(callbackId))
+		return clientError
+	})
+}
+
+// GetVariableSpecTypeContext 获取类型规格，ctx 取消时放弃等待
+func (c *Client) GetVariableSpecTypeContext(ctx context.Context, objectRef string, fc FC) (MmsType, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	value, err := await(ctx, "get variable specification", objectRef, fc, func(callbackId int32) C.IedClientError {
+		var clientError C.IedClientError
+		C.IedConnection_getVariableSpecificationAsync(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc), (*[0]byte)(C.getVariableSpecificationHandlerBridge), intToPointerBug58625(callbackId))
+		return clientError
+	})
+	if err != nil {
+		return 0, err
+	}
+	return value.(MmsType), nil
+}
+
+// WriteContext 写单个属性值，value 的取值方式与 Write 相同，ctx 取消时放弃等待。
+// 类型规格以同步请求获取，该请求不受 ctx 控制，最长等待 RequestTimeout
+func (c *Client) WriteContext(ctx context.Context, objectRef string, fc FC, value interface{}) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	// 按类型规格构造值，与 Write 一致
+	var clientError C.IedClientError
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("write", objectRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	mmsValue, err := toMmsValueBySpec(spec, value)
+	if err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	_, err = await(ctx, "write", objectRef, fc, func(callbackId int32) C.IedClientError {
+		var clientError C.IedClientError
+		C.IedConnection_writeObjectAsync(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc), mmsValue, (*[0]byte)(C.genericServiceHandlerBridge), intToPointerBug58625(callbackId))
+		return clientError
+	})
+	return err
+}
+
+// ReadDataSetContext 读取DataSet，ctx 取消时放弃等待
+func (c *Client) ReadDataSetContext(ctx context.Context, objectRef string) ([]*MmsValue, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	value, err := await(ctx, "read data set", objectRef, NONE, func(callbackId int32) C.IedClientError {
+		var clientError C.IedClientError
+		C.IedConnection_readDataSetValuesAsync(c.conn, &clientError, cObjectRef, nil, (*[0]byte)(C.readDataSetHandlerBridge), intToPointerBug58625(callbackId))
+		return clientError
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*MmsValue), nil
+}
+
+// GetRCBValuesContext 读取报告控制块，ctx 取消时放弃等待
+func (c *Client) GetRCBValuesContext(ctx context.Context, objectReference string) (*ClientReportControlBlock, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cObjectRef := C.CString(objectReference)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	value, err := await(ctx, "get RCB values", objectReference, NONE, func(callbackId int32) C.IedClientError {
+		var clientError C.IedClientError
+		C.IedConnection_getRCBValuesAsync(c.conn, &clientError, cObjectRef, nil, (*[0]byte)(C.getRCBValuesHandlerBridge), intToPointerBug58625(callbackId))
+		return clientError
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*ClientReportControlBlock), nil
+}
@@ -0,0 +1,250 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// EntryIDStore 保存 BRCB 最后收到的 EntryID，用于断线重连后从断点继续接收缓存报告
+type EntryIDStore interface {
+	// Load 读取 rcbReference 对应的 EntryID，没有记录时返回 nil, nil
+	Load(rcbReference string) ([]byte, error)
+	// Save 保存 rcbReference 对应的 EntryID，在报告接收线程中调用，耗时会延迟后续报告的处理
+	Save(rcbReference string, entryID []byte) error
+}
+
+// FileEntryIDStore 基于文件的 EntryIDStore，每个 BRCB 对应目录下的一个文件
+type FileEntryIDStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileEntryIDStore 创建基于文件的 EntryIDStore，dir 不存在时在首次保存时创建
+func NewFileEntryIDStore(dir string) *FileEntryIDStore {
+	return &FileEntryIDStore{dir: dir}
+}
+
+func (s *FileEntryIDStore) Load(rcbReference string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(rcbReference))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(string(data))
+}
+
+func (s *FileEntryIDStore) Save(rcbReference string, entryID []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，避免进程退出时留下不完整的记录
+	tmp, err := os.CreateTemp(s.dir, "entryid-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(hex.EncodeToString(entryID)); err != nil {
+		tmp.Close()
+		return err
+	}
+	// 重命名前落盘，避免掉电后记录为空文件
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(rcbReference))
+}
+
+// path 引用中含有 / 和 $，以十六进制编码作为文件名
+func (s *FileEntryIDStore) path(rcbReference string) string {
+	return filepath.Join(s.dir, hex.EncodeToString([]byte(rcbReference))+".entryid")
+}
+
+// BufferedReportHandler 缓存报告回调，gap 为 true 表示服务端缓存溢出或无法从断点继续，中间有报告丢失
+type BufferedReportHandler func(report ClientReport, gap bool)
+
+// BufferedReportSubscription 缓存报告订阅
+type BufferedReportSubscription struct {
+	client       *Client
+	rcbReference string
+	store        EntryIDStore
+	handler      BufferedReportHandler
+
+	mu          sync.Mutex
+	lastEntryID []byte
+	pendingGap  bool
+	err         error
+}
+
+// SubscribeBufferedReports 订阅 BRCB 报告，每个报告在 handler 返回后才将其 EntryID 写入 store，
+// 因此报告至少送达一次，进程在 handler 返回后、保存完成前退出时，重连后会重复收到该报告。
+// 订阅时以及自动重连的客户端重连后，会先将最后的 EntryID 写回 BRCB 再使能，使服务端补发缓存的报告。
+// 调用前应已通过 SetRCBValuesWithMask 配置好 DatSet、TrgOps 等元素，控制块已有报告回调或订阅时返回 ReportHandlerExists
+func (c *Client) SubscribeBufferedReports(rcbReference string, store EntryIDStore, handler BufferedReportHandler) (*BufferedReportSubscription, error) {
+	if !isBufferedRCB(rcbReference) {
+		return nil, UnsupportedRCBElement
+	}
+
+	entryID, err := store.Load(rcbReference)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &BufferedReportSubscription{
+		client:       c,
+		rcbReference: rcbReference,
+		store:        store,
+		handler:      handler,
+		lastEntryID:  entryID,
+	}
+
+	c.mu.Lock()
+	_, installed := c.reportHandlers[rcbReference]
+	_, subscribed := c.bufferedReports[rcbReference]
+	if installed || subscribed {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ReportHandlerExists, rcbReference)
+	}
+	c.bufferedReports[rcbReference] = sub
+	c.mu.Unlock()
+
+	if err = c.setReportHandler(rcbReference, sub.onReport); err != nil {
+		c.removeBufferedReport(sub)
+		return nil, err
+	}
+
+	if err = sub.resync(); err != nil && !(c.supervisor != nil && isNotConnected(err)) {
+		c.removeBufferedReport(sub)
+		c.UninstallReportHandler(rcbReference)
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Close 取消订阅并禁用 BRCB，已保存的 EntryID 保留在 store 中
+func (sub *BufferedReportSubscription) Close() error {
+	c := sub.client
+	c.removeBufferedReport(sub)
+	c.UninstallReportHandler(sub.rcbReference)
+	return c.SetRCBValuesWithMask(sub.rcbReference, ClientReportControlBlock{Ena: false}, RCB_ELEMENT_RPT_ENA)
+}
+
+// LastEntryID 返回最后收到的 EntryID
+func (sub *BufferedReportSubscription) LastEntryID() []byte {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return append([]byte(nil), sub.lastEntryID...)
+}
+
+// Err 返回最近一次保存 EntryID 时发生的错误
+func (sub *BufferedReportSubscription) Err() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.err
+}
+
+// resync 禁用 BRCB，写回最后的 EntryID 并打开 EntryID 和 BufOvfl 报告选项，然后重新使能
+func (sub *BufferedReportSubscription) resync() error {
+	c := sub.client
+
+	if err := c.SetRCBValuesWithMask(sub.rcbReference, ClientReportControlBlock{Ena: false}, RCB_ELEMENT_RPT_ENA); err != nil {
+		return err
+	}
+
+	rcb, err := c.GetRCBValues(sub.rcbReference)
+	if err != nil {
+		return err
+	}
+	if !rcb.OptFlds.EntryID || !rcb.OptFlds.BufferOverflow {
+		rcb.OptFlds.EntryID = true
+		rcb.OptFlds.BufferOverflow = true
+		if err = c.SetRCBValuesWithMask(sub.rcbReference, *rcb, RCB_ELEMENT_OPT_FLDS); err != nil {
+			return err
+		}
+	}
+
+	sub.mu.Lock()
+	entryID := append([]byte(nil), sub.lastEntryID...)
+	sub.mu.Unlock()
+
+	if len(entryID) > 0 {
+		rcb.EntryID = entryID
+		err = c.SetRCBValuesWithMask(sub.rcbReference, *rcb, RCB_ELEMENT_ENTRY_ID)
+		if isNotConnected(err) {
+			return err
+		}
+		// 服务端缓存中已没有该条目，只能从当前缓存开始接收
+		if err != nil {
+			sub.mu.Lock()
+			sub.pendingGap = true
+			sub.mu.Unlock()
+		}
+	}
+
+	rcb.Ena = true
+	return c.SetRCBValuesWithMask(sub.rcbReference, *rcb, RCB_ELEMENT_RPT_ENA)
+}
+
+func (sub *BufferedReportSubscription) onReport(report ClientReport) {
+	entryID := toOctetString(C.ClientReport_getEntryId(report.Report))
+
+	sub.mu.Lock()
+	gap := sub.pendingGap || (report.HasBufOvfl() && report.GetBufOvfl())
+	sub.pendingGap = false
+	sub.mu.Unlock()
+
+	// 处理完报告后再记录断点，进程在两者之间退出时重连后会重复收到该报告
+	if sub.handler != nil {
+		sub.handler(report, gap)
+	}
+
+	if entryID != nil {
+		err := sub.store.Save(sub.rcbReference, entryID)
+		sub.mu.Lock()
+		sub.lastEntryID = entryID
+		sub.err = err
+		sub.mu.Unlock()
+	}
+}
+
+func (c *Client) removeBufferedReport(sub *BufferedReportSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bufferedReports[sub.rcbReference] == sub {
+		delete(c.bufferedReports, sub.rcbReference)
+	}
+}
+
+// resyncBufferedReports 重连后从断点恢复所有缓存报告订阅
+func (c *Client) resyncBufferedReports() {
+	c.mu.RLock()
+	subs := make([]*BufferedReportSubscription, 0, len(c.bufferedReports))
+	for _, sub := range c.bufferedReports {
+		subs = append(subs, sub)
+	}
+	c.mu.RUnlock()
+
+	for _, sub := range subs {
+		// 失败的订阅在下次重连时重试
+		_ = sub.resync()
+	}
+}
@@ -0,0 +1,353 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Dbpos 双点位置
+type Dbpos int
+
+const (
+	DBPOS_INTERMEDIATE_STATE Dbpos = C.DBPOS_INTERMEDIATE_STATE
+	DBPOS_OFF                Dbpos = C.DBPOS_OFF
+	DBPOS_ON                 Dbpos = C.DBPOS_ON
+	DBPOS_BAD_STATE          Dbpos = C.DBPOS_BAD_STATE
+)
+
+func (d Dbpos) String() string {
+	switch d {
+	case DBPOS_INTERMEDIATE_STATE:
+		return "intermediate-state"
+	case DBPOS_OFF:
+		return "off"
+	case DBPOS_ON:
+		return "on"
+	case DBPOS_BAD_STATE:
+		return "bad-state"
+	default:
+		return fmt.Sprintf("Dbpos(%d)", int(d))
+	}
+}
+
+// SPS 单点状态
+type SPS struct {
+	StVal bool
+	Q     Quality
+	T     Timestamp
+}
+
+// DPS 双点状态
+type DPS struct {
+	StVal Dbpos
+	Q     Quality
+	T     Timestamp
+}
+
+// INS 整数状态
+type INS struct {
+	StVal int32
+	Q     Quality
+	T     Timestamp
+}
+
+// ENS 枚举状态，StVal 为枚举的序号
+type ENS struct {
+	StVal int32
+	Q     Quality
+	T     Timestamp
+}
+
+// MV 测量值，Mag 取 mag.f，服务端只有 mag.i 时取 mag.i
+type MV struct {
+	Mag float64
+	Q   Quality
+	T   Timestamp
+}
+
+// CMV 复数测量值，没有相角时 Ang 为 0
+type CMV struct {
+	Mag float64
+	Ang float64
+	Q   Quality
+	T   Timestamp
+}
+
+// WYE 三相星形测量值，服务端没有的相为 nil
+type WYE struct {
+	PhsA *CMV
+	PhsB *CMV
+	PhsC *CMV
+	Neut *CMV
+	Net  *CMV
+	Res  *CMV
+}
+
+// DEL 三相三角形测量值，服务端没有的相为 nil
+type DEL struct {
+	PhsAB *CMV
+	PhsBC *CMV
+	PhsCA *CMV
+}
+
+// BCR 二进制计数器
+type BCR struct {
+	ActVal int64
+	Q      Quality
+	T      Timestamp
+}
+
+// ReadSPS 读取单点状态，doRef 如 simpleIOGenericIO/GGIO1.Ind1
+func (c *Client) ReadSPS(doRef string) (*SPS, error) {
+	sps := &SPS{}
+	err := c.readCDC(doRef, ST, func(do *cdcValue) error {
+		stVal, err := do.child("stVal", Boolean)
+		if err != nil {
+			return err
+		}
+		sps.StVal = bool(C.MmsValue_getBoolean(stVal))
+		sps.Q, sps.T, err = do.qualityAndTime("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sps, nil
+}
+
+// ReadDPS 读取双点状态
+func (c *Client) ReadDPS(doRef string) (*DPS, error) {
+	dps := &DPS{}
+	err := c.readCDC(doRef, ST, func(do *cdcValue) error {
+		stVal, err := do.child("stVal", BitString)
+		if err != nil {
+			return err
+		}
+		dps.StVal = Dbpos(C.Dbpos_fromMmsValue(stVal))
+		dps.Q, dps.T, err = do.qualityAndTime("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dps, nil
+}
+
+// ReadINS 读取整数状态
+func (c *Client) ReadINS(doRef string) (*INS, error) {
+	ins := &INS{}
+	err := c.readCDC(doRef, ST, func(do *cdcValue) error {
+		stVal, err := do.child("stVal", Integer)
+		if err != nil {
+			return err
+		}
+		ins.StVal = int32(C.MmsValue_toInt32(stVal))
+		ins.Q, ins.T, err = do.qualityAndTime("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// ReadENS 读取枚举状态
+func (c *Client) ReadENS(doRef string) (*ENS, error) {
+	ins, err := c.ReadINS(doRef)
+	if err != nil {
+		return nil, err
+	}
+	return &ENS{StVal: ins.StVal, Q: ins.Q, T: ins.T}, nil
+}
+
+// ReadMV 读取测量值
+func (c *Client) ReadMV(doRef string) (*MV, error) {
+	mv := &MV{}
+	err := c.readCDC(doRef, MX, func(do *cdcValue) error {
+		mag, err := do.analogue("mag")
+		if err != nil {
+			return err
+		}
+		mv.Mag = mag
+		mv.Q, mv.T, err = do.qualityAndTime("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mv, nil
+}
+
+// ReadCMV 读取复数测量值
+func (c *Client) ReadCMV(doRef string) (*CMV, error) {
+	var cmv *CMV
+	err := c.readCDC(doRef, MX, func(do *cdcValue) (err error) {
+		cmv, err = do.cmv("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmv, nil
+}
+
+// ReadWYE 读取三相星形测量值，一次读取所有相
+func (c *Client) ReadWYE(doRef string) (*WYE, error) {
+	wye := &WYE{}
+	err := c.readCDC(doRef, MX, func(do *cdcValue) error {
+		return do.phases(map[string]**CMV{
+			"phsA": &wye.PhsA, "phsB": &wye.PhsB, "phsC": &wye.PhsC,
+			"neut": &wye.Neut, "net": &wye.Net, "res": &wye.Res,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wye, nil
+}
+
+// ReadDEL 读取三相三角形测量值，一次读取所有相
+func (c *Client) ReadDEL(doRef string) (*DEL, error) {
+	del := &DEL{}
+	err := c.readCDC(doRef, MX, func(do *cdcValue) error {
+		return do.phases(map[string]**CMV{
+			"phsAB": &del.PhsAB, "phsBC": &del.PhsBC, "phsCA": &del.PhsCA,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return del, nil
+}
+
+// ReadBCR 读取二进制计数器
+func (c *Client) ReadBCR(doRef string) (*BCR, error) {
+	bcr := &BCR{}
+	err := c.readCDC(doRef, ST, func(do *cdcValue) error {
+		actVal, err := do.child("actVal", Integer)
+		if err != nil {
+			return err
+		}
+		bcr.ActVal = int64(C.MmsValue_toInt64(actVal))
+		bcr.Q, bcr.T, err = do.qualityAndTime("")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bcr, nil
+}
+
+// cdcValue 一次读取的数据对象值及其类型规格
+type cdcValue struct {
+	spec  *C.MmsVariableSpecification
+	value *C.MmsValue
+}
+
+// readCDC 按功能约束一次读取整个数据对象，decode 返回前值有效
+func (c *Client) readCDC(doRef string, fc FC, decode func(do *cdcValue) error) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	cObjectRef := C.CString(doRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("read", doRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	value := C.IedConnection_readObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("read", doRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(value)
+
+	if MmsType(C.MmsValue_getType(value)) != Structure {
+		return fmt.Errorf("%w: %s is not a data object", TypeInconsistent, doRef)
+	}
+	return decode(&cdcValue{spec: spec, value: value})
+}
+
+// lookup 按 MMS 路径（$ 分隔）查找成员，不存在时返回 nil
+func (do *cdcValue) lookup(path string) *C.MmsValue {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return C.MmsVariableSpecification_getChildValue(do.spec, do.value, cPath)
+}
+
+// child 查找指定类型的成员
+func (do *cdcValue) child(path string, mmsType MmsType) (*C.MmsValue, error) {
+	value := do.lookup(path)
+	if value == nil {
+		return nil, fmt.Errorf("%w: %s", StructureComponentMissing, path)
+	}
+	if actual := MmsType(C.MmsValue_getType(value)); actual != mmsType {
+		return nil, fmt.Errorf("%w: %s is %s, expected %s", TypeInconsistent, path, actual, mmsType)
+	}
+	return value, nil
+}
+
+// qualityAndTime 读取 prefix 下的 q 和 t，prefix 为空时读取数据对象本身的
+func (do *cdcValue) qualityAndTime(prefix string) (Quality, Timestamp, error) {
+	q, err := do.child(prefix+"q", BitString)
+	if err != nil {
+		return 0, Timestamp{}, err
+	}
+	t, err := do.child(prefix+"t", UTCTime)
+	if err != nil {
+		return 0, Timestamp{}, err
+	}
+	return Quality(C.MmsValue_getBitStringAsInteger(q)), toTimestamp(t), nil
+}
+
+// analogue 读取 AnalogueValue，优先取浮点值 f，没有时取整数值 i
+func (do *cdcValue) analogue(path string) (float64, error) {
+	if f := do.lookup(path + "$f"); f != nil && MmsType(C.MmsValue_getType(f)) == Float {
+		return float64(C.MmsValue_toDouble(f)), nil
+	}
+	i, err := do.child(path+"$i", Integer)
+	if err != nil {
+		return 0, err
+	}
+	return float64(C.MmsValue_toInt64(i)), nil
+}
+
+// cmv 读取 prefix 下的复数测量值，prefix 为空时读取数据对象本身
+func (do *cdcValue) cmv(prefix string) (*CMV, error) {
+	mag, err := do.analogue(prefix + "cVal$mag")
+	if err != nil {
+		return nil, err
+	}
+	cmv := &CMV{Mag: mag}
+	if do.lookup(prefix+"cVal$ang") != nil {
+		if cmv.Ang, err = do.analogue(prefix + "cVal$ang"); err != nil {
+			return nil, err
+		}
+	}
+	if cmv.Q, cmv.T, err = do.qualityAndTime(prefix); err != nil {
+		return nil, err
+	}
+	return cmv, nil
+}
+
+// phases 读取 WYE、DEL 的各相，服务端没有的相保持为 nil
+func (do *cdcValue) phases(phases map[string]**CMV) error {
+	for name, target := range phases {
+		if do.lookup(name) == nil {
+			continue
+		}
+		cmv, err := do.cmv(name + "$")
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*target = cmv
+	}
+	return nil
+}
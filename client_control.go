@@ -2,7 +2,10 @@ package iec61850
 
 // #include <iec61850_client.h>
 import "C"
-import "unsafe"
+import (
+	"time"
+	"unsafe"
+)
 
 type ControlObjectParam struct {
 	CtlVal      bool
@@ -70,137 +73,47 @@ func (c *Client) ControlForDirectWithNormalSecurity(objectRef string, ctlVal boo
 }
 
 func (c *Client) ControlByControlModelINC(objectRef string, controlModel ControlModel, param *ControlObjectParamINC) error {
-	cObjectRef := C.CString(objectRef)
-	defer C.free(unsafe.Pointer(cObjectRef))
-
-	control := C.ControlObjectClient_create(cObjectRef, c.conn)
-	if control == nil {
-		return CreateControlObjectClientFail
-	}
-
-	ctlVal := C.MmsValue_newIntegerFromInt32(C.int(param.CtlVal))
-	defer C.MmsValue_delete(ctlVal)
-
-	switch controlModel {
-	case CONTROL_MODEL_SBO_NORMAL:
-		if !bool(C.ControlObjectClient_select(control)) {
-			return ControlSelectFail
-		}
-	case CONTROL_MODEL_DIRECT_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
-	case CONTROL_MODEL_SBO_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
-		if !bool(C.ControlObjectClient_selectWithValue(control, ctlVal)) {
-			return ControlSelectFail
-		}
-	}
-
-	var cOrIdent *C.char
-	if param.OrIdent != "" {
-		cOrIdent = C.CString(param.OrIdent)
-		defer C.free(unsafe.Pointer(cOrIdent))
-	}
-
-	C.ControlObjectClient_setControlModel(control, C.ControlModel(controlModel))
-	C.ControlObjectClient_setOrigin(control, cOrIdent, C.int(param.OrCat))
-	C.ControlObjectClient_setInterlockCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setSynchroCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setTestMode(control, C.bool(param.Test))
-
-	if !bool(C.ControlObjectClient_operate(control, ctlVal, 0)) {
-		return ControlObjectFail
-	}
-	return nil
+	return c.controlByControlModel(objectRef, controlModel, param.CtlVal, param.OrIdent, param.OrCat, param.Test, param.Check, param.OperateTime)
 }
 
 func (c *Client) ControlByControlModelAPC(objectRef string, controlModel ControlModel, param *ControlObjectParamAPC) error {
-	cObjectRef := C.CString(objectRef)
-	defer C.free(unsafe.Pointer(cObjectRef))
-
-	control := C.ControlObjectClient_create(cObjectRef, c.conn)
-	if control == nil {
-		return CreateControlObjectClientFail
-	}
-
-	ctlVal := C.MmsValue_newFloat(C.float(param.CtlVal))
-	defer C.MmsValue_delete(ctlVal)
-
-	switch controlModel {
-	case CONTROL_MODEL_SBO_NORMAL:
-		if !bool(C.ControlObjectClient_select(control)) {
-			return ControlSelectFail
-		}
-	case CONTROL_MODEL_DIRECT_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
-	case CONTROL_MODEL_SBO_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
-		if !bool(C.ControlObjectClient_selectWithValue(control, ctlVal)) {
-			return ControlSelectFail
-		}
-	}
-
-	var cOrIdent *C.char
-	if param.OrIdent != "" {
-		cOrIdent = C.CString(param.OrIdent)
-		defer C.free(unsafe.Pointer(cOrIdent))
-	}
-
-	C.ControlObjectClient_setControlModel(control, C.ControlModel(controlModel))
-	C.ControlObjectClient_setOrigin(control, cOrIdent, C.int(param.OrCat))
-	C.ControlObjectClient_setInterlockCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setSynchroCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setTestMode(control, C.bool(param.Test))
-
-	if !bool(C.ControlObjectClient_operate(control, ctlVal, 0)) {
-		return ControlObjectFail
-	}
-	return nil
+	return c.controlByControlModel(objectRef, controlModel, param.CtlVal, param.OrIdent, param.OrCat, param.Test, param.Check, param.OperateTime)
 }
 
 func (c *Client) ControlByControlModel(objectRef string, controlModel ControlModel, param *ControlObjectParam) error {
-	cObjectRef := C.CString(objectRef)
-	defer C.free(unsafe.Pointer(cObjectRef))
+	return c.controlByControlModel(objectRef, controlModel, param.CtlVal, param.OrIdent, param.OrCat, param.Test, param.Check, param.OperateTime)
+}
 
-	control := C.ControlObjectClient_create(cObjectRef, c.conn)
-	if control == nil {
-		return CreateControlObjectClientFail
+// controlByControlModel 按指定控制模式执行一次完整的控制，Check 同时设置联锁和同期检查
+func (c *Client) controlByControlModel(objectRef string, controlModel ControlModel, ctlVal interface{}, orIdent string, orCat int, test, check bool, operateTime uint64) error {
+	control, err := c.NewControlObject(objectRef)
+	if err != nil {
+		return err
 	}
+	defer control.Close()
 
-	defer C.ControlObjectClient_destroy(control)
-	ctlVal := C.MmsValue_newBoolean(C.bool(param.CtlVal))
-	defer C.MmsValue_delete(ctlVal)
+	control.SetControlModel(controlModel)
+	control.SetOrigin(orIdent, OrCat(orCat))
+	control.SetInterlockCheck(check)
+	control.SetSynchroCheck(check)
+	control.SetTestMode(test)
 
 	// Select before operate
 	switch controlModel {
 	case CONTROL_MODEL_SBO_NORMAL:
-		if !bool(C.ControlObjectClient_select(control)) {
-			return ControlSelectFail
-		}
-	case CONTROL_MODEL_DIRECT_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
+		err = control.Select()
 	case CONTROL_MODEL_SBO_ENHANCED:
-		C.ControlObjectClient_setCommandTerminationHandler(control, nil, nil)
-		if !bool(C.ControlObjectClient_selectWithValue(control, ctlVal)) {
-			return ControlSelectFail
-		}
+		err = control.SelectWithValue(ctlVal)
 	}
-
-	var cOrIdent *C.char
-	if param.OrIdent != "" {
-		cOrIdent = C.CString(param.OrIdent)
-		defer C.free(unsafe.Pointer(cOrIdent))
+	if err != nil {
+		return err
 	}
 
-	C.ControlObjectClient_setControlModel(control, C.ControlModel(controlModel))
-	C.ControlObjectClient_setOrigin(control, cOrIdent, C.int(param.OrCat))
-	C.ControlObjectClient_setInterlockCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setSynchroCheck(control, C.bool(param.Check))
-	C.ControlObjectClient_setTestMode(control, C.bool(param.Test))
-
-	if !bool(C.ControlObjectClient_operate(control, ctlVal, C.uint64_t(param.OperateTime))) {
-		return ControlObjectFail
+	var operTime time.Time
+	if operateTime != 0 {
+		operTime = time.UnixMilli(int64(operateTime))
 	}
-	return nil
+	return control.Operate(ctlVal, operTime)
 }
 
 // ControlForSboWithNormalSecurity 控制模式 2[sbo-with-normal-security]
@@ -0,0 +1,341 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/spf13/cast"
+)
+
+// OrCat 控制命令的发起者类别
+type OrCat int
+
+const (
+	ORCAT_NOT_SUPPORTED     OrCat = C.CONTROL_ORCAT_NOT_SUPPORTED
+	ORCAT_BAY_CONTROL       OrCat = C.CONTROL_ORCAT_BAY_CONTROL
+	ORCAT_STATION_CONTROL   OrCat = C.CONTROL_ORCAT_STATION_CONTROL
+	ORCAT_REMOTE_CONTROL    OrCat = C.CONTROL_ORCAT_REMOTE_CONTROL
+	ORCAT_AUTOMATIC_BAY     OrCat = C.CONTROL_ORCAT_AUTOMATIC_BAY
+	ORCAT_AUTOMATIC_STATION OrCat = C.CONTROL_ORCAT_AUTOMATIC_STATION
+	ORCAT_AUTOMATIC_REMOTE  OrCat = C.CONTROL_ORCAT_AUTOMATIC_REMOTE
+	ORCAT_MAINTENANCE       OrCat = C.CONTROL_ORCAT_MAINTENANCE
+	ORCAT_PROCESS           OrCat = C.CONTROL_ORCAT_PROCESS
+)
+
+// StepCommand BSC、BAC 的升降命令（Tcmd）
+type StepCommand int
+
+const (
+	STEP_STOP   StepCommand = 0
+	STEP_LOWER  StepCommand = 1
+	STEP_HIGHER StepCommand = 2
+)
+
+// ControlLastApplError LastApplError 中的错误码
+type ControlLastApplError int
+
+const (
+	CONTROL_ERROR_NO_ERROR      ControlLastApplError = C.CONTROL_ERROR_NO_ERROR
+	CONTROL_ERROR_UNKNOWN       ControlLastApplError = C.CONTROL_ERROR_UNKNOWN
+	CONTROL_ERROR_TIMEOUT_TEST  ControlLastApplError = C.CONTROL_ERROR_TIMEOUT_TEST
+	CONTROL_ERROR_OPERATOR_TEST ControlLastApplError = C.CONTROL_ERROR_OPERATOR_TEST
+)
+
+// LastApplError 服务端在控制失败时返回的 LastApplError
+type LastApplError struct {
+	CtlNum   int
+	Error    ControlLastApplError
+	AddCause ControlAddCause
+}
+
+func toLastApplError(err C.LastApplError) LastApplError {
+	return LastApplError{
+		CtlNum:   int(err.ctlNum),
+		Error:    ControlLastApplError(err.error),
+		AddCause: ControlAddCause(err.addCause),
+	}
+}
+
+// GetControlAddCause 从控制服务返回的错误中取出 AddCause，不是控制服务的 IedError 时返回 ADD_CAUSE_UNKNOWN
+func GetControlAddCause(err error) ControlAddCause {
+	var iedError *IedError
+	if errors.As(err, &iedError) {
+		return iedError.AddCause()
+	}
+	return ADD_CAUSE_UNKNOWN
+}
+
+// ControlObject 可控数据对象，支持 SPC、DPC、INC、ENC、BSC、ISC、APC、BAC。
+// 创建时从服务端读取 ctlModel 和 ctlVal 类型，使用完毕后需要 Close
+type ControlObject struct {
+	client    *Client
+	objectRef string
+	mu        sync.Mutex
+	control   C.ControlObjectClient
+
+	callbackId  int32
+	termination commandTermination
+}
+
+// NewControlObject 创建控制对象，objectRef 如 simpleIOGenericIO/GGIO1.SPCSO1
+func (c *Client) NewControlObject(objectRef string) (*ControlObject, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	control := C.ControlObjectClient_create(cObjectRef, c.conn)
+	if control == nil {
+		return nil, CreateControlObjectClientFail
+	}
+	o := &ControlObject{
+		client:    c,
+		objectRef: objectRef,
+		control:   control,
+	}
+	o.installCommandTerminationHandler()
+
+	c.mu.Lock()
+	c.controlObjects[o.callbackId] = o
+	c.mu.Unlock()
+	return o, nil
+}
+
+// Close 释放控制对象，客户端 Close 时会自动释放其创建的控制对象
+func (o *ControlObject) Close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		o.uninstallCommandTerminationHandler()
+		C.ControlObjectClient_destroy(o.control)
+		o.control = nil
+
+		o.client.mu.Lock()
+		delete(o.client.controlObjects, o.callbackId)
+		o.client.mu.Unlock()
+	}
+}
+
+// ObjectRef 返回控制对象引用
+func (o *ControlObject) ObjectRef() string {
+	return o.objectRef
+}
+
+// ControlModel 返回当前使用的控制模式，控制对象关闭后返回 CONTROL_MODEL_STATUS_ONLY
+func (o *ControlObject) ControlModel() ControlModel {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control == nil {
+		return CONTROL_MODEL_STATUS_ONLY
+	}
+	return ControlModel(C.ControlObjectClient_getControlModel(o.control))
+}
+
+// SetControlModel 仅修改客户端使用的控制模式，不写入服务端
+func (o *ControlObject) SetControlModel(controlModel ControlModel) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		C.ControlObjectClient_setControlModel(o.control, C.ControlModel(controlModel))
+	}
+}
+
+// CtlValType 返回 ctlVal 的 MMS 类型，控制对象关闭后返回 DataAccessError
+func (o *ControlObject) CtlValType() MmsType {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control == nil {
+		return DataAccessError
+	}
+	return MmsType(C.ControlObjectClient_getCtlValType(o.control))
+}
+
+// SetOrigin 设置发起者标识 orIdent 和类别 orCat
+func (o *ControlObject) SetOrigin(orIdent string, orCat OrCat) {
+	var cOrIdent *C.char
+	if orIdent != "" {
+		cOrIdent = C.CString(orIdent)
+		defer C.free(unsafe.Pointer(cOrIdent))
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		C.ControlObjectClient_setOrigin(o.control, cOrIdent, C.int(orCat))
+	}
+}
+
+// SetInterlockCheck 设置 Check 中的联锁检查位
+func (o *ControlObject) SetInterlockCheck(check bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		C.ControlObjectClient_setInterlockCheck(o.control, C.bool(check))
+	}
+}
+
+// SetSynchroCheck 设置 Check 中的同期检查位
+func (o *ControlObject) SetSynchroCheck(check bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		C.ControlObjectClient_setSynchroCheck(o.control, C.bool(check))
+	}
+}
+
+// SetTestMode 设置 Test 标志
+func (o *ControlObject) SetTestMode(test bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control != nil {
+		C.ControlObjectClient_setTestMode(o.control, C.bool(test))
+	}
+}
+
+// acquire 开始一次控制服务，客户端或控制对象已关闭时返回 NotConnected。成功时持有 o.mu，需要调用 release
+func (o *ControlObject) acquire() error {
+	if err := o.client.acquire(); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	if o.control == nil {
+		o.mu.Unlock()
+		o.client.release()
+		return NotConnected
+	}
+	return nil
+}
+
+func (o *ControlObject) release() {
+	o.mu.Unlock()
+	o.client.release()
+}
+
+// Select 选择，用于 sbo-with-normal-security
+func (o *ControlObject) Select() error {
+	if err := o.acquire(); err != nil {
+		return err
+	}
+	defer o.release()
+	if !bool(C.ControlObjectClient_select(o.control)) {
+		return o.lastError("select", ControlSelectFail)
+	}
+	return nil
+}
+
+// SelectWithValue 带值选择，用于 sbo-with-enhanced-security
+func (o *ControlObject) SelectWithValue(ctlVal interface{}) error {
+	mmsValue, err := o.toCtlVal(ctlVal)
+	if err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	if err = o.acquire(); err != nil {
+		return err
+	}
+	defer o.release()
+	if !bool(C.ControlObjectClient_selectWithValue(o.control, mmsValue)) {
+		return o.lastError("select with value", ControlSelectFail)
+	}
+	return nil
+}
+
+// Operate 执行，operTime 为零值时立即执行，否则为服务端在该时刻执行的时间激活控制（operTm）。
+// ctlVal 按 CDC 取值：SPC、DPC 为 bool；INC、ENC、ISC 为整数；APC 为浮点数；BSC、BAC 为 StepCommand
+func (o *ControlObject) Operate(ctlVal interface{}, operTime time.Time) error {
+	mmsValue, err := o.toCtlVal(ctlVal)
+	if err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	var cOperTime C.uint64_t
+	if !operTime.IsZero() {
+		cOperTime = C.uint64_t(operTime.UnixMilli())
+	}
+
+	if err = o.acquire(); err != nil {
+		return err
+	}
+	defer o.release()
+	if !bool(C.ControlObjectClient_operate(o.control, mmsValue, cOperTime)) {
+		return o.lastError("operate", ControlObjectFail)
+	}
+	return nil
+}
+
+// Cancel 取消选择或尚未执行的时间激活控制
+func (o *ControlObject) Cancel() error {
+	if err := o.acquire(); err != nil {
+		return err
+	}
+	defer o.release()
+	if !bool(C.ControlObjectClient_cancel(o.control)) {
+		return o.lastError("cancel", ControlCancelFail)
+	}
+	return nil
+}
+
+// LastApplError 返回服务端最近一次返回的 LastApplError
+func (o *ControlObject) LastApplError() LastApplError {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.control == nil {
+		return LastApplError{}
+	}
+	return toLastApplError(C.ControlObjectClient_getLastApplError(o.control))
+}
+
+// lastError 控制服务失败时返回包含客户端错误码和 LastApplError 的 IedError
+func (o *ControlObject) lastError(op string, err error) error {
+	// 调用方已持有 o.mu
+	lastApplError := toLastApplError(C.ControlObjectClient_getLastApplError(o.control))
+	return &IedError{
+		Op:            op,
+		ObjectRef:     o.objectRef,
+		FC:            CO,
+		Code:          int(C.ControlObjectClient_getLastError(o.control)),
+		LastApplError: &lastApplError,
+		Err:           err,
+	}
+}
+
+// toCtlVal 按服务端的 ctlVal 类型转换控制值
+func (o *ControlObject) toCtlVal(ctlVal interface{}) (*C.MmsValue, error) {
+	if step, ok := ctlVal.(StepCommand); ok {
+		return toTcmdMmsValue(step), nil
+	}
+
+	switch ctlValType := o.CtlValType(); ctlValType {
+	case BitString:
+		step, err := cast.ToIntE(ctlVal)
+		if err != nil {
+			return nil, err
+		}
+		return toTcmdMmsValue(StepCommand(step)), nil
+	case Integer:
+		return toInt32MmsValue(ctlVal)
+	case Unsigned:
+		return toUint32MmsValue(ctlVal)
+	case Structure:
+		// APC 的 AnalogueValue，libiec61850 接受浮点值
+		return toFloatMmsValue(ctlVal)
+	default:
+		return toMmsValue(ctlValType, ctlVal)
+	}
+}
+
+// toTcmdMmsValue Tcmd 为 2 位的编码枚举
+func toTcmdMmsValue(step StepCommand) *C.MmsValue {
+	mmsValue := C.MmsValue_newBitString(2)
+	C.MmsValue_setBitStringFromIntegerBigEndian(mmsValue, C.uint32_t(step))
+	return mmsValue
+}
@@ -0,0 +1,136 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+extern void commandTerminationHandlerBridge(void* parameter, ControlObjectClient controlClient);
+*/
+import "C"
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// commandTerminations 保存已注册命令结束回调的控制对象，key 为回调 id
+var commandTerminations sync.Map
+
+// CommandTermination 增强型安全控制模式下服务端发送的命令结束通知
+type CommandTermination struct {
+	ObjectRef     string
+	Success       bool          // true 为 CommandTermination+，false 为 CommandTermination-
+	LastApplError LastApplError // CommandTermination- 时服务端返回的附加原因
+}
+
+// Err CommandTermination+ 时返回 nil，CommandTermination- 时返回包装 ControlTerminationFail 的 IedError
+func (t CommandTermination) Err() error {
+	if t.Success {
+		return nil
+	}
+	lastApplError := t.LastApplError
+	return &IedError{
+		Op:            "command termination",
+		ObjectRef:     t.ObjectRef,
+		FC:            CO,
+		LastApplError: &lastApplError,
+		Err:           ControlTerminationFail,
+	}
+}
+
+// CommandTerminationHandler 命令结束回调，在 libiec61850 线程中执行，不能在回调中 Close 控制对象
+type CommandTerminationHandler func(termination CommandTermination)
+
+// commandTermination 控制对象的命令结束状态，与 ControlObject.mu 分开，回调可能在 Operate 返回前到达
+type commandTermination struct {
+	mu      sync.Mutex
+	handler CommandTerminationHandler
+	waiter  chan CommandTermination
+}
+
+//export commandTerminationHandlerBridge
+func commandTerminationHandlerBridge(parameter unsafe.Pointer, controlClient C.ControlObjectClient) {
+	callbackId := int32(uintptr(parameter))
+	if val, ok := commandTerminations.Load(callbackId); ok {
+		if o, ok := val.(*ControlObject); ok {
+			lastApplError := toLastApplError(C.ControlObjectClient_getLastApplError(controlClient))
+			o.onCommandTermination(CommandTermination{
+				ObjectRef:     o.objectRef,
+				Success:       lastApplError.Error == CONTROL_ERROR_NO_ERROR,
+				LastApplError: lastApplError,
+			})
+		}
+	}
+}
+
+// installCommandTerminationHandler 创建控制对象时注册回调，Close 时先注销再释放
+func (o *ControlObject) installCommandTerminationHandler() {
+	o.callbackId = callbackIdGen.Add(1)
+	commandTerminations.Store(o.callbackId, o)
+	// intToPointerBug58625 must be inlined at the C call: storing the fake unsafe.Pointer in a local would let Go 1.26's stack scanner reject it.
+	C.ControlObjectClient_setCommandTerminationHandler(o.control, (*[0]byte)(C.commandTerminationHandlerBridge), intToPointerBug58625(o.callbackId))
+}
+
+func (o *ControlObject) uninstallCommandTerminationHandler() {
+	commandTerminations.Delete(o.callbackId)
+	C.ControlObjectClient_setCommandTerminationHandler(o.control, nil, nil)
+}
+
+// SetCommandTerminationHandler 设置命令结束回调，handler 为 nil 时取消
+func (o *ControlObject) SetCommandTerminationHandler(handler CommandTerminationHandler) {
+	o.termination.mu.Lock()
+	defer o.termination.mu.Unlock()
+	o.termination.handler = handler
+}
+
+// OperateAndWait 执行并等待命令结束，用于增强型安全控制模式。
+// Operate 被拒绝时返回 Operate 的错误；timeout 内未收到命令结束时返回包装 Timeout 的 IedError；
+// 收到 CommandTermination- 时返回 CommandTermination.Err()
+func (o *ControlObject) OperateAndWait(ctlVal interface{}, operTime time.Time, timeout time.Duration) (*CommandTermination, error) {
+	waiter := make(chan CommandTermination, 1)
+	o.termination.mu.Lock()
+	o.termination.waiter = waiter
+	o.termination.mu.Unlock()
+
+	defer func() {
+		o.termination.mu.Lock()
+		if o.termination.waiter == waiter {
+			o.termination.waiter = nil
+		}
+		o.termination.mu.Unlock()
+	}()
+
+	if err := o.Operate(ctlVal, operTime); err != nil {
+		return nil, err
+	}
+
+	// 时间激活控制在 operTm 到达后才会执行
+	if !operTime.IsZero() {
+		if wait := time.Until(operTime); wait > 0 {
+			timeout += wait
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case termination := <-waiter:
+		return &termination, termination.Err()
+	case <-timer.C:
+		return nil, &IedError{Op: "operate and wait", ObjectRef: o.objectRef, FC: CO, Code: int(C.IED_ERROR_TIMEOUT)}
+	}
+}
+
+func (o *ControlObject) onCommandTermination(termination CommandTermination) {
+	o.termination.mu.Lock()
+	handler := o.termination.handler
+	waiter := o.termination.waiter
+	o.termination.waiter = nil
+	o.termination.mu.Unlock()
+
+	if waiter != nil {
+		waiter <- termination
+	}
+	if handler != nil {
+		handler(termination)
+	}
+}
@@ -0,0 +1,131 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+static void destroyMmsValues(LinkedList values) {
+	LinkedList_destroyDeep(values, (LinkedListValueDeleteFunction) MmsValue_delete);
+}
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// DataSetDirectory 数据集目录
+type DataSetDirectory struct {
+	Members   []string // 成员引用，格式为 LD/LN.DO.DA[FC]
+	Deletable bool     // 是否可删除
+}
+
+// CreateDataSet 创建数据集，dataSetReference 为 LD/LN.name 或 @name（关联专用），
+// members 为成员引用，格式为 LD/LN.DO.DA[FC]
+func (c *Client) CreateDataSet(dataSetReference string, members []string) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	cDataSetRef := C.CString(dataSetReference)
+	defer C.free(unsafe.Pointer(cDataSetRef))
+
+	dataSetElements := C.LinkedList_create()
+	defer C.LinkedList_destroy(dataSetElements)
+	for _, member := range members {
+		C.LinkedList_add(dataSetElements, unsafe.Pointer(C.CString(member)))
+	}
+
+	var clientError C.IedClientError
+	C.IedConnection_createDataSet(c.conn, &clientError, cDataSetRef, dataSetElements)
+	return newIedError("create data set", dataSetReference, NONE, clientError)
+}
+
+// DeleteDataSet 删除数据集，返回服务端是否删除了数据集
+func (c *Client) DeleteDataSet(dataSetReference string) (bool, error) {
+	if err := c.acquire(); err != nil {
+		return false, err
+	}
+	defer c.release()
+
+	cDataSetRef := C.CString(dataSetReference)
+	defer C.free(unsafe.Pointer(cDataSetRef))
+
+	var clientError C.IedClientError
+	deleted := C.IedConnection_deleteDataSet(c.conn, &clientError, cDataSetRef)
+	if err := newIedError("delete data set", dataSetReference, NONE, clientError); err != nil {
+		return false, err
+	}
+	return bool(deleted), nil
+}
+
+// GetDataSetDirectory 获取数据集成员引用及是否可删除
+func (c *Client) GetDataSetDirectory(dataSetReference string) (*DataSetDirectory, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cDataSetRef := C.CString(dataSetReference)
+	defer C.free(unsafe.Pointer(cDataSetRef))
+
+	var (
+		clientError C.IedClientError
+		isDeletable C.bool
+	)
+	dataSetMembers := C.IedConnection_getDataSetDirectory(c.conn, &clientError, cDataSetRef, &isDeletable)
+	if err := newIedError("get data set directory", dataSetReference, NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(dataSetMembers)
+
+	directory := &DataSetDirectory{
+		Members:   make([]string, 0),
+		Deletable: bool(isDeletable),
+	}
+	for member := C.LinkedList_getNext(dataSetMembers); member != nil; member = C.LinkedList_getNext(member) {
+		directory.Members = append(directory.Members, C2GoStr((*C.char)(C.LinkedList_getData(member))))
+	}
+	return directory, nil
+}
+
+// WriteDataSet 写数据集，values 与数据集成员一一对应，返回每个成员的访问结果，
+// 成功的成员为 DATA_ACCESS_ERROR_SUCCESS
+func (c *Client) WriteDataSet(dataSetReference string, values []*MmsValue) ([]MmsDataAccessError, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	cDataSetRef := C.CString(dataSetReference)
+	defer C.free(unsafe.Pointer(cDataSetRef))
+
+	cValues := C.LinkedList_create()
+	defer C.destroyMmsValues(cValues)
+	for _, value := range values {
+		mmsValue, err := toMmsValue(value.Type, value.Value)
+		if err != nil {
+			return nil, err
+		}
+		C.LinkedList_add(cValues, unsafe.Pointer(mmsValue))
+	}
+
+	var (
+		clientError   C.IedClientError
+		accessResults C.LinkedList
+	)
+	C.IedConnection_writeDataSetValues(c.conn, &clientError, cDataSetRef, cValues, &accessResults)
+	if accessResults != nil {
+		defer C.destroyMmsValues(accessResults)
+	}
+	if err := newIedError("write data set", dataSetReference, NONE, clientError); err != nil {
+		return nil, err
+	}
+
+	results := make([]MmsDataAccessError, 0, len(values))
+	if accessResults != nil {
+		for result := C.LinkedList_getNext(accessResults); result != nil; result = C.LinkedList_getNext(result) {
+			results = append(results, MmsDataAccessError(C.MmsValue_getDataAccessError((*C.MmsValue)(C.LinkedList_getData(result)))))
+		}
+	}
+	return results, nil
+}
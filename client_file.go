@@ -0,0 +1,192 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+extern bool getFileHandlerBridge(void* parameter, uint8_t* buffer, uint32_t bytesRead);
+
+static void destroyFileDirectory(LinkedList fileNames) {
+	LinkedList_destroyDeep(fileNames, (LinkedListValueDeleteFunction) FileDirectoryEntry_destroy);
+}
+*/
+import "C"
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// DefaultFilestoreBasepath 客户端本地文件存储目录的默认值，SetFile 会将待上传文件暂存于此
+const DefaultFilestoreBasepath = "./vmd-filestore/"
+
+var fileDownloads sync.Map
+
+// FileDirectoryEntry 文件目录项
+type FileDirectoryEntry struct {
+	FileName     string    // 文件名
+	FileSize     uint32    // 文件大小，单位：字节，未知时为0
+	LastModified time.Time // 最后修改时间
+}
+
+type fileDownload struct {
+	writer io.Writer
+	err    error
+}
+
+//export getFileHandlerBridge
+func getFileHandlerBridge(parameter unsafe.Pointer, buffer *C.uint8_t, bytesRead C.uint32_t) C.bool {
+	callbackId := int32(uintptr(parameter))
+	if val, ok := fileDownloads.Load(callbackId); ok {
+		if download, ok := val.(*fileDownload); ok {
+			data := C.GoBytes(unsafe.Pointer(buffer), C.int(bytesRead))
+			if _, err := download.writer.Write(data); err != nil {
+				download.err = err
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// GetFileDirectory 获取文件目录，directoryName 为空时获取根目录，服务端分多次返回时自动拼接
+func (c *Client) GetFileDirectory(directoryName string) ([]FileDirectoryEntry, error) {
+	var cDirectoryName *C.char
+	if directoryName != "" {
+		cDirectoryName = C.CString(directoryName)
+		defer C.free(unsafe.Pointer(cDirectoryName))
+	}
+
+	entries := make([]FileDirectoryEntry, 0)
+	var continueAfter string
+	for {
+		page, moreFollows, err := c.getFileDirectoryPage(cDirectoryName, continueAfter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+
+		if !moreFollows || len(page) == 0 {
+			return entries, nil
+		}
+		continueAfter = page[len(page)-1].FileName
+	}
+}
+
+func (c *Client) getFileDirectoryPage(cDirectoryName *C.char, continueAfter string) ([]FileDirectoryEntry, bool, error) {
+	if err := c.acquire(); err != nil {
+		return nil, false, err
+	}
+	defer c.release()
+
+	var cContinueAfter *C.char
+	if continueAfter != "" {
+		cContinueAfter = C.CString(continueAfter)
+		defer C.free(unsafe.Pointer(cContinueAfter))
+	}
+
+	var (
+		clientError C.IedClientError
+		moreFollows C.bool
+	)
+	fileNames := C.IedConnection_getFileDirectoryEx(c.conn, &clientError, cDirectoryName, cContinueAfter, &moreFollows)
+	if err := newIedError("get file directory", C.GoString(cDirectoryName), NONE, clientError); err != nil {
+		return nil, false, err
+	}
+	defer C.destroyFileDirectory(fileNames)
+
+	entries := make([]FileDirectoryEntry, 0)
+	for fileName := C.LinkedList_getNext(fileNames); fileName != nil; fileName = C.LinkedList_getNext(fileName) {
+		entry := C.FileDirectoryEntry(C.LinkedList_getData(fileName))
+		entries = append(entries, FileDirectoryEntry{
+			FileName:     C2GoStr(C.FileDirectoryEntry_getFileName(entry)),
+			FileSize:     uint32(C.FileDirectoryEntry_getFileSize(entry)),
+			LastModified: time.UnixMilli(int64(C.FileDirectoryEntry_getLastModified(entry))),
+		})
+	}
+	return entries, bool(moreFollows), nil
+}
+
+// GetFile 下载文件并写入 w，返回接收的字节数
+func (c *Client) GetFile(fileName string, w io.Writer) (uint32, error) {
+	if err := c.acquire(); err != nil {
+		return 0, err
+	}
+	defer c.release()
+
+	cFileName := Go2CStr(fileName)
+	defer C.free(unsafe.Pointer(cFileName))
+
+	callbackId := callbackIdGen.Add(1)
+	download := &fileDownload{writer: w}
+	fileDownloads.Store(callbackId, download)
+	defer fileDownloads.Delete(callbackId)
+
+	var clientError C.IedClientError
+	// intToPointerBug58625 must be inlined at the C call: storing the fake unsafe.Pointer in a local would let Go 1.26's stack scanner reject it.
+	bytesRead := C.IedConnection_getFile(c.conn, &clientError, cFileName, (*[0]byte)(C.getFileHandlerBridge), intToPointerBug58625(callbackId))
+	if download.err != nil {
+		return uint32(bytesRead), download.err
+	}
+	if err := newIedError("get file", fileName, NONE, clientError); err != nil {
+		return uint32(bytesRead), err
+	}
+	return uint32(bytesRead), nil
+}
+
+// SetFile 上传文件，r 的内容先暂存到本地文件存储目录，再由服务端通过 obtainFile 服务读取
+func (c *Client) SetFile(destinationFilename string, r io.Reader) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	basepath := c.settings.FilestoreBasepath
+	if basepath == "" {
+		basepath = DefaultFilestoreBasepath
+	}
+	if err := os.MkdirAll(basepath, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(basepath, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	cSourceFilename := C.CString(filepath.Base(tmp.Name()))
+	defer C.free(unsafe.Pointer(cSourceFilename))
+	cDestinationFilename := Go2CStr(destinationFilename)
+	defer C.free(unsafe.Pointer(cDestinationFilename))
+
+	var clientError C.IedClientError
+	C.IedConnection_setFile(c.conn, &clientError, cSourceFilename, cDestinationFilename)
+	return newIedError("set file", destinationFilename, NONE, clientError)
+}
+
+// DeleteFile 删除服务端文件
+func (c *Client) DeleteFile(fileName string) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	cFileName := Go2CStr(fileName)
+	defer C.free(unsafe.Pointer(cFileName))
+
+	var clientError C.IedClientError
+	C.IedConnection_deleteFile(c.conn, &clientError, cFileName)
+	return newIedError("delete file", fileName, NONE, clientError)
+}
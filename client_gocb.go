@@ -0,0 +1,136 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"unsafe"
+)
+
+type GoCBElement uint32
+
+// GoCB 元素掩码，用于 SetGoCBValues 指定写入哪些元素
+const (
+	GOCB_ELEMENT_GO_ENA      GoCBElement = C.GOCB_ELEMENT_GO_ENA
+	GOCB_ELEMENT_GO_ID       GoCBElement = C.GOCB_ELEMENT_GO_ID
+	GOCB_ELEMENT_DATSET      GoCBElement = C.GOCB_ELEMENT_DATSET
+	GOCB_ELEMENT_CONF_REV    GoCBElement = C.GOCB_ELEMENT_CONF_REV
+	GOCB_ELEMENT_NDS_COMM    GoCBElement = C.GOCB_ELEMENT_NDS_COMM
+	GOCB_ELEMENT_DST_ADDRESS GoCBElement = C.GOCB_ELEMENT_DST_ADDRESS
+	GOCB_ELEMENT_MIN_TIME    GoCBElement = C.GOCB_ELEMENT_MIN_TIME
+	GOCB_ELEMENT_MAX_TIME    GoCBElement = C.GOCB_ELEMENT_MAX_TIME
+	GOCB_ELEMENT_FIXED_OFFS  GoCBElement = C.GOCB_ELEMENT_FIXED_OFFS
+	GOCB_ELEMENT_ALL         GoCBElement = C.GOCB_ELEMENT_ALL
+)
+
+// writableGoCBElements 客户端可以写入的 GoCB 元素
+const writableGoCBElements = GOCB_ELEMENT_GO_ENA | GOCB_ELEMENT_GO_ID | GOCB_ELEMENT_DATSET | GOCB_ELEMENT_DST_ADDRESS
+
+// PhyComAddress 以太网目的地址及 VLAN 参数
+type PhyComAddress struct {
+	VlanPriority uint8
+	VlanID       uint16
+	AppID        uint16
+	DstAddr      [6]uint8
+}
+
+type ClientGooseControlBlock struct {
+	GoEna      bool          // 使能
+	GoID       string        // GOOSE 标识
+	DatSet     string        // 数据集
+	ConfRev    uint32        // 配置版本号，只读
+	NdsCom     bool          // 需要调试，只读
+	DstAddress PhyComAddress // 目的地址，61850-7-2 规定只读，部分服务端（如 libiec61850）允许在 GoEna 为 false 时写入
+	MinTime    uint32        // 最小重传时间，单位：毫秒，只读
+	MaxTime    uint32        // 最大重传时间，单位：毫秒，只读
+	FixedOffs  bool          // 固定偏移编码，只读
+}
+
+// GetGoCBValues 读取 GOOSE 控制块，objectReference 如 simpleIOGenericIO/LLN0.gcbEvents
+func (c *Client) GetGoCBValues(objectReference string) (*ClientGooseControlBlock, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	cObjectRef := C.CString(objectReference)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	goCB := C.IedConnection_getGoCBValues(c.conn, &clientError, cObjectRef, nil)
+	if goCB == nil {
+		return nil, newIedError("get GoCB values", objectReference, NONE, clientError)
+	}
+	defer C.ClientGooseControlBlock_destroy(goCB)
+
+	if err := newIedError("get GoCB values", objectReference, NONE, clientError); err != nil {
+		return nil, err
+	}
+
+	dstAddress := C.ClientGooseControlBlock_getDstAddress(goCB)
+	block := &ClientGooseControlBlock{
+		GoEna:   bool(C.ClientGooseControlBlock_getGoEna(goCB)),
+		GoID:    C.GoString(C.ClientGooseControlBlock_getGoID(goCB)),
+		DatSet:  C.GoString(C.ClientGooseControlBlock_getDatSet(goCB)),
+		ConfRev: uint32(C.ClientGooseControlBlock_getConfRev(goCB)),
+		NdsCom:  bool(C.ClientGooseControlBlock_getNdsComm(goCB)),
+		DstAddress: PhyComAddress{
+			VlanPriority: uint8(dstAddress.vlanPriority),
+			VlanID:       uint16(dstAddress.vlanId),
+			AppID:        uint16(dstAddress.appId),
+		},
+		MinTime:   uint32(C.ClientGooseControlBlock_getMinTime(goCB)),
+		MaxTime:   uint32(C.ClientGooseControlBlock_getMaxTime(goCB)),
+		FixedOffs: bool(C.ClientGooseControlBlock_getFixedOffs(goCB)),
+	}
+	for i := range block.DstAddress.DstAddr {
+		block.DstAddress.DstAddr[i] = uint8(dstAddress.dstAddress[i])
+	}
+	return block, nil
+}
+
+// SetGoCBValues 写 GOOSE 控制块，mask 指定写入的元素，只能写入 GoEna、GoID、DatSet 和 DstAddress。
+// 写入 DstAddress 前需要禁用 GoCB，服务端不允许修改时返回错误
+func (c *Client) SetGoCBValues(objectReference string, settings ClientGooseControlBlock, mask GoCBElement) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	if mask&^writableGoCBElements != 0 {
+		return ReadOnlyGoCBElement
+	}
+
+	var clientError C.IedClientError
+	cObjectRef := C.CString(objectReference)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	goCB := C.ClientGooseControlBlock_create(cObjectRef)
+	defer C.ClientGooseControlBlock_destroy(goCB)
+
+	if mask&GOCB_ELEMENT_GO_ENA != 0 {
+		C.ClientGooseControlBlock_setGoEna(goCB, C.bool(settings.GoEna))
+	}
+	if mask&GOCB_ELEMENT_GO_ID != 0 {
+		cGoID := C.CString(settings.GoID)
+		defer C.free(unsafe.Pointer(cGoID))
+		C.ClientGooseControlBlock_setGoID(goCB, cGoID)
+	}
+	if mask&GOCB_ELEMENT_DATSET != 0 {
+		cDatSet := C.CString(settings.DatSet)
+		defer C.free(unsafe.Pointer(cDatSet))
+		C.ClientGooseControlBlock_setDatSet(goCB, cDatSet)
+	}
+	if mask&GOCB_ELEMENT_DST_ADDRESS != 0 {
+		var dstAddress C.PhyComAddress
+		dstAddress.vlanPriority = C.uint8_t(settings.DstAddress.VlanPriority)
+		dstAddress.vlanId = C.uint16_t(settings.DstAddress.VlanID)
+		dstAddress.appId = C.uint16_t(settings.DstAddress.AppID)
+		for i, b := range settings.DstAddress.DstAddr {
+			dstAddress.dstAddress[i] = C.uint8_t(b)
+		}
+		C.ClientGooseControlBlock_setDstAddress(goCB, dstAddress)
+	}
+
+	C.IedConnection_setGoCBValues(c.conn, &clientError, goCB, C.uint32_t(mask), true)
+	return newIedError("set GoCB values", objectReference, NONE, clientError)
+}
@@ -0,0 +1,57 @@
+package iec61850
+
+import "sync"
+
+// callGuard 跟踪使用连接的调用，关闭开始后拒绝新的调用，并在进行中的调用全部结束后通知关闭方
+type callGuard struct {
+	mu      sync.Mutex
+	calls   int
+	closing bool
+	idle    chan struct{} // closing 后进行中的调用全部结束时关闭
+}
+
+func (g *callGuard) acquire() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closing {
+		return false
+	}
+	g.calls++
+	return true
+}
+
+func (g *callGuard) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls--
+	if g.closing && g.calls == 0 {
+		close(g.idle)
+	}
+}
+
+// close 拒绝新的调用，返回等待进行中调用结束的 channel，已经关闭过时返回 nil
+func (g *callGuard) close() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closing {
+		return nil
+	}
+	g.closing = true
+	g.idle = make(chan struct{})
+	if g.calls == 0 {
+		close(g.idle)
+	}
+	return g.idle
+}
+
+// acquire 开始一次使用连接的调用，客户端已关闭或正在关闭时返回 NotConnected，成功时需要调用 release
+func (c *Client) acquire() error {
+	if !c.guard.acquire() {
+		return NotConnected
+	}
+	return nil
+}
+
+func (c *Client) release() {
+	c.guard.release()
+}
@@ -0,0 +1,90 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import "unsafe"
+
+// ServerIdentity MMS identify 服务返回的服务端标识，对应服务端的 SetServerIdentity
+type ServerIdentity struct {
+	Vendor   string
+	Model    string
+	Revision string
+}
+
+// Identify 读取服务端的厂商、型号和版本，服务端不支持 identify 服务时返回错误
+func (c *Client) Identify() (*ServerIdentity, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	var mmsError C.MmsError
+	identity := C.MmsConnection_identify(C.IedConnection_getMmsConnection(c.conn), &mmsError)
+	if err := newMmsIedError("identify", "", NONE, mmsError); err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return nil, UnexpectedValueReceived
+	}
+	defer C.MmsServerIdentity_destroy(identity)
+
+	return &ServerIdentity{
+		Vendor:   C.GoString(identity.vendorName),
+		Model:    C.GoString(identity.modelName),
+		Revision: C.GoString(identity.revision),
+	}, nil
+}
+
+// GetServerDirectory 返回服务端的逻辑设备名
+func (c *Client) GetServerDirectory() ([]string, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	devices := C.IedConnection_getServerDirectory(c.conn, &clientError, false)
+	if err := newIedError("get server directory", "", NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(devices)
+	return toStrings(devices), nil
+}
+
+// GetLogicalDeviceVariables 返回逻辑设备中所有 MMS 变量名，如 LLN0$ST$Mod$stVal
+func (c *Client) GetLogicalDeviceVariables(ld string) ([]string, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	cLd := C.CString(ld)
+	defer C.free(unsafe.Pointer(cLd))
+
+	variables := C.IedConnection_getLogicalDeviceVariables(c.conn, &clientError, cLd)
+	if err := newIedError("get logical device variables", ld, NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(variables)
+	return toStrings(variables), nil
+}
+
+// GetLogicalDeviceDataSets 返回逻辑设备中所有数据集名，如 LLN0$dataset1
+func (c *Client) GetLogicalDeviceDataSets(ld string) ([]string, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	cLd := C.CString(ld)
+	defer C.free(unsafe.Pointer(cLd))
+
+	dataSets := C.IedConnection_getLogicalDeviceDataSets(c.conn, &clientError, cLd)
+	if err := newIedError("get logical device data sets", ld, NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(dataSets)
+	return toStrings(dataSets), nil
+}
@@ -0,0 +1,102 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// IsoSelectors OSI 选择器，为空时不发送对应的选择器
+type IsoSelectors struct {
+	PSel []byte // 表示层选择器，最多 16 字节
+	SSel []byte // 会话层选择器，最多 16 字节
+	TSel []byte // 传输层选择器，最多 4 字节
+}
+
+// applyIsoParameters 将 Settings 中的 ACSE 认证、AP title、选择器和本地地址写入连接参数，需要在连接前调用
+func (c *Client) applyIsoParameters() error {
+	settings := c.settings
+	params := C.MmsConnection_getIsoConnectionParameters(C.IedConnection_getMmsConnection(c.conn))
+
+	if settings.AcsePassword != "" {
+		cPassword := C.CString(settings.AcsePassword)
+		defer C.free(unsafe.Pointer(cPassword))
+
+		// 认证参数在连接期间一直被使用，客户端销毁时释放
+		c.acseAuth = C.AcseAuthenticationParameter_create()
+		C.AcseAuthenticationParameter_setAuthMechanism(c.acseAuth, C.ACSE_AUTH_PASSWORD)
+		C.AcseAuthenticationParameter_setPassword(c.acseAuth, cPassword)
+		C.IsoConnectionParameters_setAcseAuthenticationParameter(params, c.acseAuth)
+	}
+
+	if settings.RemoteApTitle != "" {
+		cApTitle := C.CString(settings.RemoteApTitle)
+		defer C.free(unsafe.Pointer(cApTitle))
+		C.IsoConnectionParameters_setRemoteApTitle(params, cApTitle, C.int(settings.RemoteAeQualifier))
+	}
+	if settings.LocalApTitle != "" {
+		cApTitle := C.CString(settings.LocalApTitle)
+		defer C.free(unsafe.Pointer(cApTitle))
+		C.IsoConnectionParameters_setLocalApTitle(params, cApTitle, C.int(settings.LocalAeQualifier))
+	}
+
+	if settings.RemoteSelectors != nil {
+		pSel, sSel, tSel, err := settings.RemoteSelectors.toC()
+		if err != nil {
+			return err
+		}
+		C.IsoConnectionParameters_setRemoteAddresses(params, pSel, sSel, tSel)
+	}
+	if settings.LocalSelectors != nil {
+		pSel, sSel, tSel, err := settings.LocalSelectors.toC()
+		if err != nil {
+			return err
+		}
+		C.IsoConnectionParameters_setLocalAddresses(params, pSel, sSel, tSel)
+	}
+
+	if settings.LocalAddress != "" {
+		// 连接参数只保存指针，客户端销毁时释放
+		c.localAddress = C.CString(settings.LocalAddress)
+		C.IedConnection_setLocalAddress(c.conn, c.localAddress, C.int(settings.LocalPort))
+	}
+	return nil
+}
+
+// destroyIsoParameters 释放 applyIsoParameters 分配的内存，需要在连接销毁后调用
+func (c *Client) destroyIsoParameters() {
+	if c.acseAuth != nil {
+		C.AcseAuthenticationParameter_destroy(c.acseAuth)
+		c.acseAuth = nil
+	}
+	if c.localAddress != nil {
+		C.free(unsafe.Pointer(c.localAddress))
+		c.localAddress = nil
+	}
+}
+
+func (s *IsoSelectors) toC() (C.PSelector, C.SSelector, C.TSelector, error) {
+	var (
+		pSel C.PSelector
+		sSel C.SSelector
+		tSel C.TSelector
+	)
+	if len(s.PSel) > len(pSel.value) || len(s.SSel) > len(sSel.value) || len(s.TSel) > len(tSel.value) {
+		return pSel, sSel, tSel, fmt.Errorf("%w: selector too long", UserProvidedInvalidArgument)
+	}
+
+	pSel.size = C.uint8_t(len(s.PSel))
+	for i, b := range s.PSel {
+		pSel.value[i] = C.uint8_t(b)
+	}
+	sSel.size = C.uint8_t(len(s.SSel))
+	for i, b := range s.SSel {
+		sSel.value[i] = C.uint8_t(b)
+	}
+	tSel.size = C.uint8_t(len(s.TSel))
+	for i, b := range s.TSel {
+		tSel.value[i] = C.uint8_t(b)
+	}
+	return pSel, sSel, tSel, nil
+}
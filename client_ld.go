@@ -4,146 +4,248 @@ package iec61850
 import "C"
 import (
 	"fmt"
+	"strings"
 	"unsafe"
 )
 
+// GetLogicalDeviceList 读取数据模型，发生错误时返回空模型
+//
+// Deprecated: 使用 GetDataModel，可以获取错误信息
 func (c *Client) GetLogicalDeviceList() DataModel {
+	dataModel, err := c.GetDataModel()
+	if err != nil {
+		return DataModel{}
+	}
+	return *dataModel
+}
+
+// GetDataModel 从服务端逐级读取 LD、LN、DO、DA，以及每个 LN 下的数据集和各类控制块，
+// DA 包含功能约束和 MMS 类型
+func (c *Client) GetDataModel() (*DataModel, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 	deviceList := C.IedConnection_getLogicalDeviceList(c.conn, &clientError)
+	if err := newIedError("get logical device list", "", NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(deviceList)
 
-	var dataModel DataModel
-
-	device := deviceList.next
-	for device != nil {
-
-		var ld LD
-		ld.Data = C2GoStr((*C.char)(device.data))
-
-		logicalNodes := C.IedConnection_getLogicalDeviceDirectory(c.conn, &clientError, (*C.char)(device.data))
-		logicalNode := logicalNodes.next
-
-		for logicalNode != nil {
-			func() {
-				var ln LN
-				ln.Data = C2GoStr((*C.char)(logicalNode.data))
-				lnRef := fmt.Sprintf("%s/%s", ld.Data, C2GoStr((*C.char)(logicalNode.data)))
-				cRef := Go2CStr(lnRef)
-				defer C.free(unsafe.Pointer(cRef))
-				dataObjects := C.IedConnection_getLogicalNodeDirectory(c.conn, &clientError, cRef, C.ACSI_CLASS_DATA_OBJECT)
-				dataObject := dataObjects.next
-				for dataObject != nil {
-					var do DO
-					do.Data = C2GoStr((*C.char)(dataObject.data))
-
-					dataObject = dataObject.next
-					doRef := fmt.Sprintf("%s/%s.%s", C2GoStr((*C.char)(device.data)), C2GoStr((*C.char)(logicalNode.data)), do.Data)
-
-					var das []DA
-					c.GetDAs(doRef, das)
-
-					do.DAs = das
-					ln.DOs = append(ln.DOs, do)
-				}
-				C.LinkedList_destroy(dataObjects)
-				clnRef := Go2CStr(lnRef)
-				defer C.free(unsafe.Pointer(clnRef))
-				dataSets := C.IedConnection_getLogicalNodeDirectory(c.conn, &clientError, clnRef, C.ACSI_CLASS_DATA_SET)
-				dataSet := dataSets.next
-				for dataSet != nil {
-					func() {
-						var ds DS
-						ds.Data = C2GoStr((*C.char)(dataSet.data))
-						var isDeletable C.bool
-						dataSetRef := fmt.Sprintf("%s.%s", lnRef, ds.Data)
-						cdataSetRef := Go2CStr(dataSetRef)
-						defer C.free(unsafe.Pointer(cdataSetRef))
-
-						dataSetMembers := C.IedConnection_getDataSetDirectory(c.conn, &clientError, cdataSetRef, &isDeletable)
-						if isDeletable {
-							fmt.Printf("    Data set: %s (deletable)\n", ds.Data)
-						} else {
-							fmt.Printf("    Data set: %s (not deletable)\n", ds.Data)
-						}
-						dataSetMemberRef := dataSetMembers.next
-						for dataSetMemberRef != nil {
-							var dsRef DSRef
-							dsRef.Data = C2GoStr((*C.char)(dataSetMemberRef.data))
-							ds.DSRefs = append(ds.DSRefs, dsRef)
-
-							dataSetMemberRef = dataSetMemberRef.next
-						}
-						C.LinkedList_destroy(dataSetMembers)
-						dataSet = dataSet.next
-						ln.DSs = append(ln.DSs, ds)
-					}()
-				}
-				C.LinkedList_destroy(dataSets)
-
-				clnRef1 := Go2CStr(lnRef)
-				defer C.free(unsafe.Pointer(clnRef1))
-
-				reports := C.IedConnection_getLogicalNodeDirectory(c.conn, &clientError, clnRef1, C.ACSI_CLASS_URCB)
-				report := reports.next
-				for report != nil {
-					var r URReport
-					r.Data = C2GoStr((*C.char)(report.data))
-					ln.URReports = append(ln.URReports, r)
-
-					report = report.next
-				}
-				C.LinkedList_destroy(reports)
-
-				clnRef2 := Go2CStr(lnRef)
-				defer C.free(unsafe.Pointer(clnRef2))
-
-				reports = C.IedConnection_getLogicalNodeDirectory(c.conn, &clientError, clnRef2, C.ACSI_CLASS_BRCB)
-				report = reports.next
-				for report != nil {
-					var r BRReport
-					r.Data = C2GoStr((*C.char)(report.data))
-					ln.BRReports = append(ln.BRReports, r)
-
-					report = report.next
-				}
-
-				C.LinkedList_destroy(reports)
-
-				ld.LNs = append(ld.LNs, ln)
-
-				logicalNode = logicalNode.next
-			}()
+	dataModel := &DataModel{}
+	for device := C.LinkedList_getNext(deviceList); device != nil; device = C.LinkedList_getNext(device) {
+		ld, err := c.getLogicalDevice(C2GoStr((*C.char)(C.LinkedList_getData(device))))
+		if err != nil {
+			return nil, err
 		}
-		C.LinkedList_destroy(logicalNodes)
-
 		dataModel.LDs = append(dataModel.LDs, ld)
+	}
+	return dataModel, nil
+}
 
-		device = device.next
+func (c *Client) getLogicalDevice(ldName string) (LD, error) {
+	ld := LD{Data: ldName}
+
+	cLdName := Go2CStr(ldName)
+	defer C.free(unsafe.Pointer(cLdName))
+
+	var clientError C.IedClientError
+	logicalNodes := C.IedConnection_getLogicalDeviceDirectory(c.conn, &clientError, cLdName)
+	if err := newIedError("get logical device directory", ldName, NONE, clientError); err != nil {
+		return ld, err
+	}
+	defer C.LinkedList_destroy(logicalNodes)
+
+	for logicalNode := C.LinkedList_getNext(logicalNodes); logicalNode != nil; logicalNode = C.LinkedList_getNext(logicalNode) {
+		ln, err := c.getLogicalNode(ldName, C2GoStr((*C.char)(C.LinkedList_getData(logicalNode))))
+		if err != nil {
+			return ld, err
+		}
+		ld.LNs = append(ld.LNs, ln)
 	}
-	C.LinkedList_destroy(deviceList)
-	return dataModel
+	return ld, nil
 }
 
+func (c *Client) getLogicalNode(ldName, lnName string) (LN, error) {
+	ln := LN{Data: lnName}
+	lnRef := fmt.Sprintf("%s/%s", ldName, lnName)
+
+	dataObjects, err := c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_DATA_OBJECT)
+	if err != nil {
+		return ln, err
+	}
+	for _, name := range dataObjects {
+		do, err := c.getDataObject(fmt.Sprintf("%s.%s", lnRef, name), name)
+		if err != nil {
+			return ln, err
+		}
+		ln.DOs = append(ln.DOs, do)
+	}
+
+	dataSets, err := c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_DATA_SET)
+	if err != nil {
+		return ln, err
+	}
+	for _, name := range dataSets {
+		directory, err := c.GetDataSetDirectory(fmt.Sprintf("%s.%s", lnRef, name))
+		if err != nil {
+			return ln, err
+		}
+		ds := DS{Data: name, Deletable: directory.Deletable}
+		for _, member := range directory.Members {
+			ds.DSRefs = append(ds.DSRefs, DSRef{Data: member})
+		}
+		ln.DSs = append(ln.DSs, ds)
+	}
+
+	names, err := c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_URCB)
+	if err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.URReports = append(ln.URReports, URReport{Data: name})
+	}
+
+	if names, err = c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_BRCB); err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.BRReports = append(ln.BRReports, BRReport{Data: name})
+	}
+
+	if names, err = c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_LCB); err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.LCBs = append(ln.LCBs, LCB{Data: name})
+	}
+
+	if names, err = c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_LOG); err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.Logs = append(ln.Logs, Log{Data: name})
+	}
+
+	if names, err = c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_GoCB); err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.GoCBs = append(ln.GoCBs, GoCB{Data: name})
+	}
+
+	if names, err = c.getLogicalNodeDirectory(lnRef, C.ACSI_CLASS_SGCB); err != nil {
+		return ln, err
+	}
+	for _, name := range names {
+		ln.SGCBs = append(ln.SGCBs, SGCB{Data: name})
+	}
+	return ln, nil
+}
+
+func (c *Client) getLogicalNodeDirectory(lnRef string, acsiClass C.ACSIClass) ([]string, error) {
+	cLnRef := Go2CStr(lnRef)
+	defer C.free(unsafe.Pointer(cLnRef))
+
+	var clientError C.IedClientError
+	list := C.IedConnection_getLogicalNodeDirectory(c.conn, &clientError, cLnRef, acsiClass)
+	if err := newIedError("get logical node directory", lnRef, NONE, clientError); err != nil {
+		return nil, err
+	}
+	defer C.LinkedList_destroy(list)
+	return toStrings(list), nil
+}
+
+// GetDAs 获取数据对象下的数据属性，写入 das 已有的元素中，发生错误时不写入
+//
+// Deprecated: 使用 GetDataAttributes，可以获取全部数据属性和错误信息
 func (c *Client) GetDAs(doRef string, das []DA) {
+	attributes, err := c.GetDataAttributes(doRef)
+	if err != nil {
+		return
+	}
+	copy(das, attributes)
+}
+
+// GetDataAttributes 获取数据对象下的数据属性，doRef 如 simpleIOGenericIO/GGIO1.SPCSO1
+func (c *Client) GetDataAttributes(doRef string) ([]DA, error) {
+	do, err := c.getDataObject(doRef, "")
+	if err != nil {
+		return nil, err
+	}
+	return do.DAs, nil
+}
+
+func (c *Client) getDataObject(doRef, name string) (DO, error) {
+	do := DO{Data: name}
+
+	cDoRef := Go2CStr(doRef)
+	defer C.free(unsafe.Pointer(cDoRef))
 
 	var clientError C.IedClientError
+	list := C.IedConnection_getDataDirectoryFC(c.conn, &clientError, cDoRef)
+	if err := newIedError("get data directory", doRef, NONE, clientError); err != nil {
+		return do, err
+	}
+	defer C.LinkedList_destroy(list)
+
+	// 属性名带有功能约束，如 stVal[ST]
+	for _, entry := range toStrings(list) {
+		daName, fcName, ok := strings.Cut(strings.TrimSuffix(entry, "]"), "[")
+		if !ok {
+			sdo, err := c.getDataObject(fmt.Sprintf("%s.%s", doRef, entry), entry)
+			if err != nil {
+				return do, err
+			}
+			do.DOs = append(do.DOs, sdo)
+			continue
+		}
 
-	cdoRef := Go2CStr(doRef)
-	defer C.free(unsafe.Pointer(cdoRef))
+		da, err := c.getDataAttribute(fmt.Sprintf("%s.%s", doRef, daName), daName, toFC(fcName))
+		if err != nil {
+			return do, err
+		}
+		do.DAs = append(do.DAs, da)
+	}
+	return do, nil
+}
 
-	dataAttributes := C.IedConnection_getDataDirectory(c.conn, &clientError, cdoRef)
-	defer C.LinkedList_destroy(dataAttributes)
-	if dataAttributes != nil {
-		dataAttribute := dataAttributes.next
+func (c *Client) getDataAttribute(daRef, name string, fc FC) (DA, error) {
+	cDaRef := Go2CStr(daRef)
+	defer C.free(unsafe.Pointer(cDaRef))
 
-		for dataAttribute != nil {
-			var da DA
-			da.Data = C2GoStr((*C.char)(dataAttribute.data))
-			das = append(das, da)
+	var clientError C.IedClientError
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cDaRef, C.FunctionalConstraint(fc))
+	if err := newIedError("get variable specification", daRef, fc, clientError); err != nil {
+		return DA{}, err
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+	return toDataAttribute(name, fc, spec), nil
+}
 
-			dataAttribute = dataAttribute.next
-			daRef := fmt.Sprintf("%s.%s", doRef, da.Data)
-			c.GetDAs(daRef, das)
+// toDataAttribute 根据类型规格构造数据属性，结构体类型递归展开子属性
+func toDataAttribute(name string, fc FC, spec *C.MmsVariableSpecification) DA {
+	da := DA{
+		Data: name,
+		FC:   fc,
+		Type: toSpecMmsType(spec),
+	}
+	if da.Type == Structure {
+		size := int(C.MmsVariableSpecification_getSize(spec))
+		for i := 0; i < size; i++ {
+			child := C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))
+			da.DAs = append(da.DAs, toDataAttribute(C2GoStr(C.MmsVariableSpecification_getName(child)), fc, child))
 		}
 	}
+	return da
+}
 
+func toStrings(list C.LinkedList) []string {
+	values := make([]string, 0)
+	for element := C.LinkedList_getNext(list); element != nil; element = C.LinkedList_getNext(element) {
+		values = append(values, C2GoStr((*C.char)(C.LinkedList_getData(element))))
+	}
+	return values
 }
@@ -0,0 +1,229 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+static void destroyJournalEntries(LinkedList journalEntries) {
+	LinkedList_destroyDeep(journalEntries, (LinkedListValueDeleteFunction) MmsJournalEntry_destroy);
+}
+*/
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+// journalReasonCodeTag 日志条目中原因码变量的标签
+const journalReasonCodeTag = "ReasonCode"
+
+// JournalEntry 日志条目
+type JournalEntry struct {
+	EntryID []byte             // 条目标识符
+	Time    time.Time          // 发生时间
+	Data    []JournalEntryData // 记录的数据
+}
+
+// JournalEntryData 日志条目中的单个数据
+type JournalEntryData struct {
+	DataReference string             // 数据引用
+	Value         *MmsValue          // 数据值
+	ReasonCode    ReasonForInclusion // 原因码
+}
+
+// QueryLogByTime 按时间范围查询日志，logReference 格式为 <LD name>/<LN name>$<log name>，
+// 返回值 moreFollows 表示服务端还有更多匹配的条目
+func (c *Client) QueryLogByTime(logReference string, startTime, endTime time.Time) ([]JournalEntry, bool, error) {
+	if err := c.acquire(); err != nil {
+		return nil, false, err
+	}
+	defer c.release()
+
+	cLogRef := C.CString(logReference)
+	defer C.free(unsafe.Pointer(cLogRef))
+
+	var (
+		clientError C.IedClientError
+		moreFollows C.bool
+	)
+	journalEntries := C.IedConnection_queryLogByTime(c.conn, &clientError, cLogRef, C.uint64_t(startTime.UnixMilli()), C.uint64_t(endTime.UnixMilli()), &moreFollows)
+	if err := newIedError("query log", logReference, NONE, clientError); err != nil {
+		return nil, false, err
+	}
+	defer C.destroyJournalEntries(journalEntries)
+
+	entries, err := toJournalEntries(journalEntries)
+	if err != nil {
+		return nil, false, err
+	}
+	return entries, bool(moreFollows), nil
+}
+
+// QueryLogAfter 查询指定条目之后的日志，entryID 和 timestamp 通常取上次收到的最后一个条目
+func (c *Client) QueryLogAfter(logReference string, entryID []byte, timestamp time.Time) ([]JournalEntry, bool, error) {
+	if err := c.acquire(); err != nil {
+		return nil, false, err
+	}
+	defer c.release()
+
+	cLogRef := C.CString(logReference)
+	defer C.free(unsafe.Pointer(cLogRef))
+
+	cEntryID := C.MmsValue_newOctetString(C.int(len(entryID)), C.int(len(entryID)))
+	defer C.MmsValue_delete(cEntryID)
+	if len(entryID) > 0 {
+		C.MmsValue_setOctetString(cEntryID, (*C.uint8_t)(unsafe.Pointer(&entryID[0])), C.int(len(entryID)))
+	}
+
+	var (
+		clientError C.IedClientError
+		moreFollows C.bool
+	)
+	journalEntries := C.IedConnection_queryLogAfter(c.conn, &clientError, cLogRef, cEntryID, C.uint64_t(timestamp.UnixMilli()), &moreFollows)
+	if err := newIedError("query log", logReference, NONE, clientError); err != nil {
+		return nil, false, err
+	}
+	defer C.destroyJournalEntries(journalEntries)
+
+	entries, err := toJournalEntries(journalEntries)
+	if err != nil {
+		return nil, false, err
+	}
+	return entries, bool(moreFollows), nil
+}
+
+// QueryLog 返回按时间范围遍历日志的迭代器，服务端分页返回时自动继续查询
+func (c *Client) QueryLog(logReference string, startTime, endTime time.Time) *LogIterator {
+	return &LogIterator{
+		client:       c,
+		logReference: logReference,
+		startTime:    startTime,
+		endTime:      endTime,
+	}
+}
+
+// LogIterator 日志迭代器，用法与 bufio.Scanner 相同：
+//
+//	it := client.QueryLog(logRef, start, end)
+//	for it.Next() {
+//		entry := it.Entry()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type LogIterator struct {
+	client       *Client
+	logReference string
+	startTime    time.Time
+	endTime      time.Time
+
+	entries     []JournalEntry
+	entry       JournalEntry
+	started     bool
+	moreFollows bool
+	err         error
+}
+
+// Next 移动到下一个条目，没有更多条目或发生错误时返回 false
+func (it *LogIterator) Next() bool {
+	for len(it.entries) == 0 {
+		if it.err != nil || (it.started && !it.moreFollows) {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.entry, it.entries = it.entries[0], it.entries[1:]
+	if it.entry.Time.After(it.endTime) {
+		it.entries = nil
+		it.moreFollows = false
+		return false
+	}
+	return true
+}
+
+func (it *LogIterator) fetch() {
+	if !it.started {
+		it.started = true
+		it.entries, it.moreFollows, it.err = it.client.QueryLogByTime(it.logReference, it.startTime, it.endTime)
+	} else {
+		it.entries, it.moreFollows, it.err = it.client.QueryLogAfter(it.logReference, it.entry.EntryID, it.entry.Time)
+	}
+
+	// 没有返回条目时无法确定继续查询的位置
+	if it.err == nil && len(it.entries) == 0 {
+		it.moreFollows = false
+	}
+}
+
+// Entry 返回当前条目
+func (it *LogIterator) Entry() JournalEntry {
+	return it.entry
+}
+
+// Err 返回迭代过程中发生的错误
+func (it *LogIterator) Err() error {
+	return it.err
+}
+
+func toJournalEntries(journalEntries C.LinkedList) ([]JournalEntry, error) {
+	entries := make([]JournalEntry, 0)
+	for element := C.LinkedList_getNext(journalEntries); element != nil; element = C.LinkedList_getNext(element) {
+		journalEntry := C.MmsJournalEntry(C.LinkedList_getData(element))
+
+		entryID := C.MmsJournalEntry_getEntryID(journalEntry)
+		entry := JournalEntry{
+			EntryID: C.GoBytes(unsafe.Pointer(C.MmsValue_getOctetStringBuffer(entryID)), C.int(C.MmsValue_getOctetStringSize(entryID))),
+			Time:    time.UnixMilli(int64(C.MmsValue_getBinaryTimeAsUtcMs(C.MmsJournalEntry_getOccurenceTime(journalEntry)))),
+		}
+
+		variables := C.MmsJournalEntry_getJournalVariables(journalEntry)
+		for variableElement := C.LinkedList_getNext(variables); variableElement != nil; variableElement = C.LinkedList_getNext(variableElement) {
+			variable := C.MmsJournalVariable(C.LinkedList_getData(variableElement))
+			tag := C.GoString(C.MmsJournalVariable_getTag(variable))
+			value := C.MmsJournalVariable_getValue(variable)
+
+			// 原因码紧跟在所属数据之后
+			if tag == journalReasonCodeTag {
+				if len(entry.Data) > 0 && MmsType(C.MmsValue_getType(value)) == BitString {
+					entry.Data[len(entry.Data)-1].ReasonCode = toReasonForInclusion(value)
+				}
+				continue
+			}
+
+			mmsType := MmsType(C.MmsValue_getType(value))
+			goValue, err := toGoValue(value, mmsType)
+			if err != nil {
+				return nil, err
+			}
+			entry.Data = append(entry.Data, JournalEntryData{
+				DataReference: tag,
+				Value: &MmsValue{
+					Type:  mmsType,
+					Value: goValue,
+				},
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// toReasonForInclusion 将原因码位串转换为 ReasonForInclusion，位 0 保留
+func toReasonForInclusion(reasonCode *C.MmsValue) ReasonForInclusion {
+	reasons := []ReasonForInclusion{
+		IEC61850_REASON_DATA_CHANGE,
+		IEC61850_REASON_QUALITY_CHANGE,
+		IEC61850_REASON_DATA_UPDATE,
+		IEC61850_REASON_INTEGRITY,
+		IEC61850_REASON_GI,
+		IEC61850_REASON_UNKNOWN,
+	}
+
+	var reason ReasonForInclusion
+	size := int(C.MmsValue_getBitStringSize(reasonCode))
+	for i, r := range reasons {
+		if i+1 < size && bool(C.MmsValue_getBitStringBit(reasonCode, C.int(i+1))) {
+			reason |= r
+		}
+	}
+	return reason
+}
@@ -0,0 +1,273 @@
+package iec61850
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wendy512/iec61850/scl"
+)
+
+// IedEndpoint SCD 中一个 IED 服务端访问点的连接配置
+type IedEndpoint struct {
+	IedName    string
+	APName     string
+	SubNetwork string
+	// Settings Host、AP title、AE qualifier 和 OSI 选择器取自 SCL 的地址参数，其余取自公共配置
+	Settings Settings
+}
+
+// IedManagerSettings 多 IED 管理器配置
+type IedManagerSettings struct {
+	Settings  Settings          // 所有客户端的公共配置，如端口和超时
+	Reconnect ReconnectSettings // 自动重连配置，其中的 StateHandler 不使用，由 StateHandler 代替
+	IedNames  []string          // 只管理这些 IED，为空时管理 SCL 中所有配置了 IP 地址的 IED
+	// StateHandler 任意 IED 连接状态变化时回调，在 libiec61850 的线程中执行，可为空
+	StateHandler func(endpoint IedEndpoint, state ConnectionState)
+}
+
+func NewIedManagerSettings() IedManagerSettings {
+	return IedManagerSettings{
+		Settings:  NewSettings(),
+		Reconnect: NewReconnectSettings(),
+	}
+}
+
+// IedHealth 单个 IED 访问点的连接状态
+type IedHealth struct {
+	IedName string
+	APName  string
+	Host    string
+	State   ConnectionState
+	Since   time.Time // 进入当前状态的时间
+}
+
+// ManagerHealth 管理器中所有 IED 访问点的连接状态汇总
+type ManagerHealth struct {
+	Total     int
+	Connected int
+	IEDs      []IedHealth
+}
+
+// Healthy 所有 IED 访问点都已连接时返回 true
+func (h ManagerHealth) Healthy() bool {
+	return h.Total > 0 && h.Connected == h.Total
+}
+
+// IedManager 按 SCD 的 Communication 部分为每个 IED 服务端访问点创建一个自动重连的客户端
+type IedManager struct {
+	ieds   []*managedIed
+	byName map[string]*managedIed // key 为 IED 名和 IED 名/访问点名
+}
+
+type managedIed struct {
+	endpoint IedEndpoint
+	client   *Client
+
+	mu    sync.Mutex
+	state ConnectionState
+	since time.Time
+}
+
+// NewIedManager 为 SCL 中每个配置了 IP 地址的 IED 服务端访问点创建自动重连的客户端，连接在后台建立。
+// settings.IedNames 中的 IED 在 SCL 中找不到 IP 地址时返回错误
+func NewIedManager(doc *scl.SCL, settings IedManagerSettings) (*IedManager, error) {
+	endpoints, err := IedEndpoints(doc, settings.Settings)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.IedNames) > 0 {
+		endpoints = slices.DeleteFunc(endpoints, func(endpoint IedEndpoint) bool {
+			return !slices.Contains(settings.IedNames, endpoint.IedName)
+		})
+		for _, name := range settings.IedNames {
+			if !slices.ContainsFunc(endpoints, func(endpoint IedEndpoint) bool { return endpoint.IedName == name }) {
+				return nil, fmt.Errorf("%w: IED %s has no IP address in SCL", UserProvidedInvalidArgument, name)
+			}
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no IED with an IP address in SCL", UserProvidedInvalidArgument)
+	}
+
+	m := &IedManager{byName: make(map[string]*managedIed)}
+	for _, endpoint := range endpoints {
+		ied := &managedIed{endpoint: endpoint, state: IED_STATE_CLOSED, since: time.Now()}
+		reconnect := settings.Reconnect
+		reconnect.StateHandler = func(state ConnectionState) {
+			ied.setState(state)
+			if settings.StateHandler != nil {
+				settings.StateHandler(ied.endpoint, state)
+			}
+		}
+
+		if ied.client, err = NewSupervisedClient(endpoint.Settings, reconnect); err != nil {
+			m.Close()
+			return nil, fmt.Errorf("%s/%s: %w", endpoint.IedName, endpoint.APName, err)
+		}
+		m.ieds = append(m.ieds, ied)
+		if _, ok := m.byName[endpoint.IedName]; !ok {
+			m.byName[endpoint.IedName] = ied
+		}
+		m.byName[endpoint.IedName+"/"+endpoint.APName] = ied
+	}
+	return m, nil
+}
+
+// IedEndpoints 从 SCL 中找出所有配置了 IP 地址的 IED 服务端访问点，按 IED 在 SCL 中的顺序排列
+func IedEndpoints(doc *scl.SCL, settings Settings) ([]IedEndpoint, error) {
+	if doc.Communication == nil {
+		return nil, fmt.Errorf("%w: SCL has no Communication section", UserProvidedInvalidArgument)
+	}
+
+	endpoints := make([]IedEndpoint, 0)
+	for _, ied := range doc.IEDs {
+		for _, ap := range ied.AccessPoints {
+			if ap.Server == nil {
+				continue
+			}
+			subNetwork, connectedAP := doc.Communication.LookupConnectedAP(ied.Name, ap.Name)
+			if connectedAP == nil || connectedAP.Address.GetParameter("IP") == "" {
+				continue
+			}
+
+			endpointSettings, err := addressSettings(settings, connectedAP.Address)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", ied.Name, ap.Name, err)
+			}
+			endpoints = append(endpoints, IedEndpoint{
+				IedName:    ied.Name,
+				APName:     ap.Name,
+				SubNetwork: subNetwork.Name,
+				Settings:   endpointSettings,
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// Client 返回 IED 的客户端，IED 有多个服务端访问点时返回 SCL 中的第一个
+func (m *IedManager) Client(iedName string) (*Client, bool) {
+	ied, ok := m.byName[iedName]
+	if !ok {
+		return nil, false
+	}
+	return ied.client, true
+}
+
+// AccessPointClient 返回 IED 指定访问点的客户端
+func (m *IedManager) AccessPointClient(iedName, apName string) (*Client, bool) {
+	return m.Client(iedName + "/" + apName)
+}
+
+// IedNames 返回管理的 IED 名，按 SCL 中的顺序排列
+func (m *IedManager) IedNames() []string {
+	names := make([]string, 0, len(m.ieds))
+	for _, ied := range m.ieds {
+		if !slices.Contains(names, ied.endpoint.IedName) {
+			names = append(names, ied.endpoint.IedName)
+		}
+	}
+	return names
+}
+
+// Endpoints 返回管理的所有 IED 访问点
+func (m *IedManager) Endpoints() []IedEndpoint {
+	endpoints := make([]IedEndpoint, 0, len(m.ieds))
+	for _, ied := range m.ieds {
+		endpoints = append(endpoints, ied.endpoint)
+	}
+	return endpoints
+}
+
+// Health 返回所有 IED 访问点的连接状态
+func (m *IedManager) Health() ManagerHealth {
+	health := ManagerHealth{Total: len(m.ieds), IEDs: make([]IedHealth, 0, len(m.ieds))}
+	for _, ied := range m.ieds {
+		ied.mu.Lock()
+		state, since := ied.state, ied.since
+		ied.mu.Unlock()
+
+		if state == IED_STATE_CONNECTED {
+			health.Connected++
+		}
+		health.IEDs = append(health.IEDs, IedHealth{
+			IedName: ied.endpoint.IedName,
+			APName:  ied.endpoint.APName,
+			Host:    ied.endpoint.Settings.Host,
+			State:   state,
+			Since:   since,
+		})
+	}
+	return health
+}
+
+// Close 并发关闭所有客户端并等待关闭完成
+func (m *IedManager) Close() {
+	var wg sync.WaitGroup
+	for _, ied := range m.ieds {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			client.Close()
+		}(ied.client)
+	}
+	wg.Wait()
+}
+
+func (ied *managedIed) setState(state ConnectionState) {
+	ied.mu.Lock()
+	defer ied.mu.Unlock()
+	if ied.state != state {
+		ied.state = state
+		ied.since = time.Now()
+	}
+}
+
+// addressSettings 将 SCL 地址参数中的 IP、OSI-AP-Title、OSI-AE-Qualifier 和 OSI 选择器写入连接配置
+func addressSettings(settings Settings, address *scl.Address) (Settings, error) {
+	settings.Host = address.GetParameter("IP")
+
+	if apTitle := address.GetParameter("OSI-AP-Title"); apTitle != "" {
+		// SCL 中 AP title 以逗号分隔，如 1,3,9999,33
+		settings.RemoteApTitle = strings.ReplaceAll(apTitle, ",", ".")
+	}
+	if aeQualifier := address.GetParameter("OSI-AE-Qualifier"); aeQualifier != "" {
+		qualifier, err := strconv.Atoi(aeQualifier)
+		if err != nil {
+			return settings, fmt.Errorf("OSI-AE-Qualifier: %w", err)
+		}
+		settings.RemoteAeQualifier = qualifier
+	}
+
+	pSel, sSel, tSel := address.GetParameter("OSI-PSEL"), address.GetParameter("OSI-SSEL"), address.GetParameter("OSI-TSEL")
+	if pSel == "" && sSel == "" && tSel == "" {
+		return settings, nil
+	}
+	// 未配置的选择器使用 libiec61850 的默认值
+	selectors := &IsoSelectors{PSel: []byte{0, 0, 0, 1}, SSel: []byte{0, 1}, TSel: []byte{0, 1}}
+	for _, selector := range []struct {
+		name  string
+		value string
+		dst   *[]byte
+	}{
+		{"OSI-PSEL", pSel, &selectors.PSel},
+		{"OSI-SSEL", sSel, &selectors.SSel},
+		{"OSI-TSEL", tSel, &selectors.TSel},
+	} {
+		if selector.value == "" {
+			continue
+		}
+		data, err := hex.DecodeString(selector.value)
+		if err != nil {
+			return settings, fmt.Errorf("%s: %w", selector.name, err)
+		}
+		*selector.dst = data
+	}
+	settings.RemoteSelectors = selectors
+	return settings, nil
+}
@@ -0,0 +1,284 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// MmsClient 直接按 MMS 域名和变量名访问服务端，用于不遵循 IEC 61850 对象引用的 MMS 设备。
+// 与创建它的 Client 共用连接，Client 关闭后所有方法返回 NotConnected
+type MmsClient struct {
+	client *Client
+}
+
+// MmsObjectClass GetNameList 查询的对象类别
+type MmsObjectClass int
+
+const (
+	MMS_OBJECT_CLASS_DOMAIN              MmsObjectClass = iota // 域，忽略 domainId
+	MMS_OBJECT_CLASS_NAMED_VARIABLE                            // 命名变量，domainId 为空时查询 VMD 范围
+	MMS_OBJECT_CLASS_NAMED_VARIABLE_LIST                       // 命名变量列表，domainId 为空时查询 VMD 范围
+)
+
+// MmsAlternateAccess 备选访问，只读写变量的一部分
+type MmsAlternateAccess struct {
+	Index     int    // 数组下标，小于 0 时不按下标访问
+	Count     int    // 从 Index 开始的元素个数，0 表示只访问 Index 一个元素，此时值为元素本身而不是数组
+	Component string // 结构体成员名，多级成员用 $ 分隔，为空时访问整个变量或元素
+}
+
+// NewArrayAccess 访问数组从 index 开始的 count 个元素
+func NewArrayAccess(index, count int) *MmsAlternateAccess {
+	return &MmsAlternateAccess{Index: index, Count: count}
+}
+
+// NewComponentAccess 访问结构体成员 component，index 不小于 0 时访问数组第 index 个元素的成员
+func NewComponentAccess(index int, component string) *MmsAlternateAccess {
+	return &MmsAlternateAccess{Index: index, Component: component}
+}
+
+// MmsVariableSpec 变量访问属性中的类型描述
+type MmsVariableSpec struct {
+	Name       string
+	Type       MmsType            // 整数类型按位宽细分
+	Size       int                // 数组元素个数、结构体成员个数，或位串、八位组串、字符串的长度，负数表示可变长度
+	Element    *MmsVariableSpec   // 数组的元素类型
+	Components []*MmsVariableSpec // 结构体成员
+}
+
+// MmsClient 返回共用该连接的 MMS 访问视图
+func (c *Client) MmsClient() *MmsClient {
+	return &MmsClient{client: c}
+}
+
+// GetNameList 查询域、命名变量或命名变量列表的名称
+func (m *MmsClient) GetNameList(class MmsObjectClass, domainId string) ([]string, error) {
+	if err := m.client.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.client.release()
+
+	var cDomainId *C.char
+	if domainId != "" {
+		cDomainId = C.CString(domainId)
+		defer C.free(unsafe.Pointer(cDomainId))
+	}
+
+	var (
+		mmsError C.MmsError
+		names    C.LinkedList
+	)
+	mmsConnection := m.mmsConnection()
+	switch class {
+	case MMS_OBJECT_CLASS_DOMAIN:
+		names = C.MmsConnection_getDomainNames(mmsConnection, &mmsError)
+	case MMS_OBJECT_CLASS_NAMED_VARIABLE:
+		if cDomainId == nil {
+			names = C.MmsConnection_getVMDVariableNames(mmsConnection, &mmsError)
+		} else {
+			names = C.MmsConnection_getDomainVariableNames(mmsConnection, &mmsError, cDomainId)
+		}
+	case MMS_OBJECT_CLASS_NAMED_VARIABLE_LIST:
+		names = C.MmsConnection_getDomainVariableListNames(mmsConnection, &mmsError, cDomainId)
+	default:
+		return nil, fmt.Errorf("%w: object class %d", UserProvidedInvalidArgument, class)
+	}
+	if err := newMmsIedError("get name list", domainId, NONE, mmsError); err != nil {
+		return nil, err
+	}
+	if names == nil {
+		return []string{}, nil
+	}
+	defer C.LinkedList_destroy(names)
+	return toStrings(names), nil
+}
+
+// ReadVariable 读取域 domainId 中的变量 itemId，access 为 nil 时读取整个变量
+func (m *MmsClient) ReadVariable(domainId, itemId string, access *MmsAlternateAccess) (*MmsValue, error) {
+	if err := m.client.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.client.release()
+
+	cDomainId := C.CString(domainId)
+	defer C.free(unsafe.Pointer(cDomainId))
+	cItemId := C.CString(itemId)
+	defer C.free(unsafe.Pointer(cItemId))
+
+	var (
+		mmsError C.MmsError
+		value    *C.MmsValue
+	)
+	mmsConnection := m.mmsConnection()
+	switch {
+	case access == nil:
+		value = C.MmsConnection_readVariable(mmsConnection, &mmsError, cDomainId, cItemId)
+	case access.Index < 0 && access.Component != "":
+		cComponent := C.CString(access.Component)
+		defer C.free(unsafe.Pointer(cComponent))
+		value = C.MmsConnection_readVariableComponent(mmsConnection, &mmsError, cDomainId, cItemId, cComponent)
+	case access.Index >= 0 && access.Component == "":
+		value = C.MmsConnection_readArrayElements(mmsConnection, &mmsError, cDomainId, cItemId, C.uint32_t(access.Index), C.uint32_t(access.Count))
+	case access.Index >= 0 && access.Count == 0:
+		cComponent := C.CString(access.Component)
+		defer C.free(unsafe.Pointer(cComponent))
+		value = C.MmsConnection_readSingleArrayElementWithComponent(mmsConnection, &mmsError, cDomainId, cItemId, C.uint32_t(access.Index), cComponent)
+	default:
+		return nil, access.invalid()
+	}
+	if err := newMmsIedError("read variable", domainId+"/"+itemId, NONE, mmsError); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, UnexpectedValueReceived
+	}
+	defer C.MmsValue_delete(value)
+
+	mmsType := MmsType(C.MmsValue_getType(value))
+	goValue, err := toGoValue(value, mmsType)
+	if err != nil {
+		return nil, err
+	}
+	return &MmsValue{Type: mmsType, Value: goValue}, nil
+}
+
+// WriteVariable 写入域 domainId 中的变量 itemId，access 为 nil 时写入整个变量。
+// value 按变量访问属性转换，取值方式与 Client.Write 相同；写入数组的多个元素时传入 Count 个元素的切片
+func (m *MmsClient) WriteVariable(domainId, itemId string, access *MmsAlternateAccess, value interface{}) error {
+	if err := m.client.acquire(); err != nil {
+		return err
+	}
+	defer m.client.release()
+
+	cDomainId := C.CString(domainId)
+	defer C.free(unsafe.Pointer(cDomainId))
+	cItemId := C.CString(itemId)
+	defer C.free(unsafe.Pointer(cItemId))
+
+	var mmsError C.MmsError
+	mmsConnection := m.mmsConnection()
+	spec := C.MmsConnection_getVariableAccessAttributes(mmsConnection, &mmsError, cDomainId, cItemId)
+	if err := newMmsIedError("write variable", domainId+"/"+itemId, NONE, mmsError); err != nil {
+		return err
+	}
+	if spec == nil {
+		return UnexpectedValueReceived
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	mmsValue, err := access.toMmsValue(spec, value)
+	if err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	var result C.MmsDataAccessError
+	switch {
+	case access == nil:
+		result = C.MmsConnection_writeVariable(mmsConnection, &mmsError, cDomainId, cItemId, mmsValue)
+	case access.Index < 0:
+		cComponent := C.CString(access.Component)
+		defer C.free(unsafe.Pointer(cComponent))
+		result = C.MmsConnection_writeVariableComponent(mmsConnection, &mmsError, cDomainId, cItemId, cComponent, mmsValue)
+	case access.Component == "":
+		result = C.MmsConnection_writeArrayElements(mmsConnection, &mmsError, cDomainId, cItemId, C.int(access.Index), C.int(access.Count), mmsValue)
+	default:
+		cComponent := C.CString(access.Component)
+		defer C.free(unsafe.Pointer(cComponent))
+		result = C.MmsConnection_writeSingleArrayElementWithComponent(mmsConnection, &mmsError, cDomainId, cItemId, C.uint32_t(access.Index), cComponent, mmsValue)
+	}
+	if err = newMmsIedError("write variable", domainId+"/"+itemId, NONE, mmsError); err != nil {
+		return err
+	}
+	if dataAccessError := MmsDataAccessError(result); dataAccessError != DATA_ACCESS_ERROR_SUCCESS {
+		return &IedError{Op: "write variable", ObjectRef: domainId + "/" + itemId, FC: NONE, Err: dataAccessError}
+	}
+	return nil
+}
+
+// GetVariableAccessAttributes 读取域 domainId 中变量 itemId 的类型描述
+func (m *MmsClient) GetVariableAccessAttributes(domainId, itemId string) (*MmsVariableSpec, error) {
+	if err := m.client.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.client.release()
+
+	cDomainId := C.CString(domainId)
+	defer C.free(unsafe.Pointer(cDomainId))
+	cItemId := C.CString(itemId)
+	defer C.free(unsafe.Pointer(cItemId))
+
+	var mmsError C.MmsError
+	spec := C.MmsConnection_getVariableAccessAttributes(m.mmsConnection(), &mmsError, cDomainId, cItemId)
+	if err := newMmsIedError("get variable access attributes", domainId+"/"+itemId, NONE, mmsError); err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return nil, UnexpectedValueReceived
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+	return toMmsVariableSpec(spec), nil
+}
+
+func (m *MmsClient) mmsConnection() C.MmsConnection {
+	return C.IedConnection_getMmsConnection(m.client.conn)
+}
+
+// toMmsValue 按备选访问选中部分的类型规格构造写入的值
+func (a *MmsAlternateAccess) toMmsValue(spec *C.MmsVariableSpecification, value interface{}) (*C.MmsValue, error) {
+	if a == nil {
+		return toMmsValueBySpec(spec, value)
+	}
+	if a.Index < 0 && a.Component == "" || a.Index >= 0 && a.Component != "" && a.Count != 0 {
+		return nil, a.invalid()
+	}
+
+	if a.Index >= 0 {
+		if MmsType(C.MmsVariableSpecification_getType(spec)) != Array {
+			return nil, fmt.Errorf("%w: variable is not an array", UserProvidedInvalidArgument)
+		}
+		spec = C.MmsVariableSpecification_getArrayElementSpecification(spec)
+	}
+	if a.Component != "" {
+		cComponent := C.CString(a.Component)
+		defer C.free(unsafe.Pointer(cComponent))
+		if spec = C.MmsVariableSpecification_getNamedVariableRecursive(spec, cComponent); spec == nil {
+			return nil, fmt.Errorf("%w: %s", StructureComponentMissing, a.Component)
+		}
+	}
+
+	if a.Count > 0 {
+		switch v := value.(type) {
+		case *MmsValue:
+			value = v.Value
+		case MmsValue:
+			value = v.Value
+		}
+		return toArrayElementsMmsValue(spec, a.Count, value)
+	}
+	return toMmsValueBySpec(spec, value)
+}
+
+func (a *MmsAlternateAccess) invalid() error {
+	return fmt.Errorf("%w: unsupported alternate access %+v", UserProvidedInvalidArgument, *a)
+}
+
+func toMmsVariableSpec(spec *C.MmsVariableSpecification) *MmsVariableSpec {
+	variableSpec := &MmsVariableSpec{
+		Name: C.GoString(C.MmsVariableSpecification_getName(spec)),
+		Type: toSpecMmsType(spec),
+		Size: int(C.MmsVariableSpecification_getSize(spec)),
+	}
+	switch variableSpec.Type {
+	case Array:
+		variableSpec.Element = toMmsVariableSpec(C.MmsVariableSpecification_getArrayElementSpecification(spec))
+	case Structure:
+		for i := 0; i < variableSpec.Size; i++ {
+			child := C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))
+			variableSpec.Components = append(variableSpec.Components, toMmsVariableSpec(child))
+		}
+	}
+	return variableSpec
+}
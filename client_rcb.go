@@ -5,6 +5,7 @@ package iec61850
 import "C"
 
 import (
+	"time"
 	"unsafe"
 )
 
@@ -27,47 +28,129 @@ type OptFlds struct {
 	ConfigRevision     bool // 配置版本号
 }
 
+type RCBElement uint32
+
+// RCB 元素掩码，用于 SetRCBValuesWithMask 指定写入哪些元素
+const (
+	RCB_ELEMENT_RPT_ID        RCBElement = C.RCB_ELEMENT_RPT_ID
+	RCB_ELEMENT_RPT_ENA       RCBElement = C.RCB_ELEMENT_RPT_ENA
+	RCB_ELEMENT_RESV          RCBElement = C.RCB_ELEMENT_RESV // 仅 URCB
+	RCB_ELEMENT_DATSET        RCBElement = C.RCB_ELEMENT_DATSET
+	RCB_ELEMENT_CONF_REV      RCBElement = C.RCB_ELEMENT_CONF_REV
+	RCB_ELEMENT_OPT_FLDS      RCBElement = C.RCB_ELEMENT_OPT_FLDS
+	RCB_ELEMENT_BUF_TM        RCBElement = C.RCB_ELEMENT_BUF_TM
+	RCB_ELEMENT_SQ_NUM        RCBElement = C.RCB_ELEMENT_SQ_NUM
+	RCB_ELEMENT_TRG_OPS       RCBElement = C.RCB_ELEMENT_TRG_OPS
+	RCB_ELEMENT_INTG_PD       RCBElement = C.RCB_ELEMENT_INTG_PD
+	RCB_ELEMENT_GI            RCBElement = C.RCB_ELEMENT_GI
+	RCB_ELEMENT_PURGE_BUF     RCBElement = C.RCB_ELEMENT_PURGE_BUF     // 仅 BRCB
+	RCB_ELEMENT_ENTRY_ID      RCBElement = C.RCB_ELEMENT_ENTRY_ID      // 仅 BRCB
+	RCB_ELEMENT_TIME_OF_ENTRY RCBElement = C.RCB_ELEMENT_TIME_OF_ENTRY // 仅 BRCB
+	RCB_ELEMENT_RESV_TMS      RCBElement = C.RCB_ELEMENT_RESV_TMS      // 仅 BRCB
+	RCB_ELEMENT_OWNER         RCBElement = C.RCB_ELEMENT_OWNER
+)
+
+const (
+	// writableRCBElements 客户端可以写入的 RCB 元素
+	writableRCBElements = RCB_ELEMENT_RPT_ID | RCB_ELEMENT_RPT_ENA | RCB_ELEMENT_RESV | RCB_ELEMENT_DATSET |
+		RCB_ELEMENT_OPT_FLDS | RCB_ELEMENT_BUF_TM | RCB_ELEMENT_TRG_OPS | RCB_ELEMENT_INTG_PD | RCB_ELEMENT_GI |
+		RCB_ELEMENT_PURGE_BUF | RCB_ELEMENT_ENTRY_ID | RCB_ELEMENT_RESV_TMS
+	// bufferedOnlyRCBElements 只有 BRCB 才有的元素
+	bufferedOnlyRCBElements = RCB_ELEMENT_PURGE_BUF | RCB_ELEMENT_ENTRY_ID | RCB_ELEMENT_TIME_OF_ENTRY | RCB_ELEMENT_RESV_TMS
+	// unbufferedOnlyRCBElements 只有 URCB 才有的元素
+	unbufferedOnlyRCBElements = RCB_ELEMENT_RESV
+)
+
 type ClientReportControlBlock struct {
-	Ena     bool    // 使能
-	IntgPd  int     // 周期上送时间
-	Resv    bool    // Reservation for URCB
-	TrgOps  TrgOps  // 触发条件
-	OptFlds OptFlds // 报告选项
+	Buffered   bool      // 是否为缓存报告控制块(BRCB)，只读
+	RptID      string    // 报告标识
+	Ena        bool      // 使能
+	DatSet     string    // 数据集，格式为 LD/LN$DataSetName
+	ConfRev    uint32    // 配置版本号，只读
+	OptFlds    OptFlds   // 报告选项
+	BufTm      uint32    // 缓存时间，单位：毫秒
+	SqNum      uint16    // 顺序号，只读
+	TrgOps     TrgOps    // 触发条件
+	IntgPd     int       // 周期上送时间
+	GI         bool      // 总召
+	Resv       bool      // Reservation for URCB
+	PurgeBuf   bool      // 清空缓存，仅 BRCB
+	EntryID    []byte    // 条目标识符，仅 BRCB
+	EntryTime  time.Time // 条目时间，仅 BRCB，只读
+	HasResvTms bool      // 服务端是否支持 ResvTms，只读
+	ResvTms    int16     // 保留时间，单位：秒，仅 BRCB
+	Owner      []byte    // 占用者，只读
 }
 
 func (c *Client) GetRCBValues(objectReference string) (*ClientReportControlBlock, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 	rcb := C.IedConnection_getRCBValues(c.conn, &clientError, cObjectRef, nil)
 	if rcb == nil {
-		return nil, GetIedClientError(clientError)
+		return nil, newIedError("get RCB values", objectReference, NONE, clientError)
+	}
+	defer C.ClientReportControlBlock_destroy(rcb)
+	return toClientReportControlBlock(rcb), nil
+}
+
+func toClientReportControlBlock(rcb C.ClientReportControlBlock) *ClientReportControlBlock {
+	block := &ClientReportControlBlock{
+		Buffered: bool(C.ClientReportControlBlock_isBuffered(rcb)),
+		RptID:    C2GoStr(C.ClientReportControlBlock_getRptId(rcb)),
+		Ena:      getRCBEnable(rcb),
+		DatSet:   C2GoStr(C.ClientReportControlBlock_getDataSetReference(rcb)),
+		ConfRev:  uint32(C.ClientReportControlBlock_getConfRev(rcb)),
+		OptFlds:  getOptFlds(rcb),
+		BufTm:    uint32(C.ClientReportControlBlock_getBufTm(rcb)),
+		SqNum:    uint16(C.ClientReportControlBlock_getSqNum(rcb)),
+		TrgOps:   getTrgOps(rcb),
+		IntgPd:   int(getRCBIntgPd(rcb)),
+		GI:       bool(C.ClientReportControlBlock_getGI(rcb)),
+		Resv:     getRCBResv(rcb),
+		Owner:    toOctetString(C.ClientReportControlBlock_getOwner(rcb)),
+	}
+	if block.Buffered {
+		block.PurgeBuf = bool(C.ClientReportControlBlock_getPurgeBuf(rcb))
+		block.EntryID = toOctetString(C.ClientReportControlBlock_getEntryId(rcb))
+		block.EntryTime = time.UnixMilli(int64(C.ClientReportControlBlock_getEntryTime(rcb)))
+		block.HasResvTms = bool(C.ClientReportControlBlock_hasResvTms(rcb))
+		if block.HasResvTms {
+			block.ResvTms = int16(C.ClientReportControlBlock_getResvTms(rcb))
+		}
 	}
-	return &ClientReportControlBlock{
-		Ena:     c.getRCBEnable(rcb),
-		IntgPd:  int(c.getRCBIntgPd(rcb)),
-		Resv:    c.getRCBResv(rcb),
-		TrgOps:  c.getTrgOps(rcb),
-		OptFlds: c.getOptFlds(rcb),
-	}, nil
+	return block
 }
 
-func (c *Client) getRCBEnable(rcb C.ClientReportControlBlock) bool {
+// toOctetString 复制 OctetString 的内容，value 为空或不是 OctetString 时返回 nil
+func toOctetString(value *C.MmsValue) []byte {
+	if value == nil || MmsType(C.MmsValue_getType(value)) != OctetString {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(C.MmsValue_getOctetStringBuffer(value)), C.int(C.MmsValue_getOctetStringSize(value)))
+}
+
+func getRCBEnable(rcb C.ClientReportControlBlock) bool {
 	enable := C.ClientReportControlBlock_getRptEna(rcb)
 	return bool(enable)
 }
 
-func (c *Client) getRCBIntgPd(rcb C.ClientReportControlBlock) uint32 {
+func getRCBIntgPd(rcb C.ClientReportControlBlock) uint32 {
 	intgPd := C.ClientReportControlBlock_getIntgPd(rcb)
 	return uint32(intgPd)
 }
 
-func (c *Client) getRCBResv(rcb C.ClientReportControlBlock) bool {
+func getRCBResv(rcb C.ClientReportControlBlock) bool {
 	resv := C.ClientReportControlBlock_getResv(rcb)
 	return bool(resv)
 }
 
-func (c *Client) getOptFlds(rcb C.ClientReportControlBlock) OptFlds {
+func getOptFlds(rcb C.ClientReportControlBlock) OptFlds {
 	optFlds := C.ClientReportControlBlock_getOptFlds(rcb)
 	g := int(optFlds)
 	return OptFlds{
@@ -82,7 +165,7 @@ func (c *Client) getOptFlds(rcb C.ClientReportControlBlock) OptFlds {
 	}
 }
 
-func (c *Client) getTrgOps(rcb C.ClientReportControlBlock) TrgOps {
+func getTrgOps(rcb C.ClientReportControlBlock) TrgOps {
 	trgOps := C.ClientReportControlBlock_getTrgOps(rcb)
 	g := int(trgOps)
 	return TrgOps{
@@ -95,74 +178,153 @@ func (c *Client) getTrgOps(rcb C.ClientReportControlBlock) TrgOps {
 	}
 }
 
+// SetRCBValues 写报告控制块的使能、触发条件、周期上送时间，URCB 同时写入 Resv
 func (c *Client) SetRCBValues(objectReference string, settings ClientReportControlBlock) error {
+	mask := RCB_ELEMENT_RPT_ENA | RCB_ELEMENT_TRG_OPS | RCB_ELEMENT_INTG_PD
+	if !isBufferedRCB(objectReference) {
+		mask |= RCB_ELEMENT_RESV
+	}
+	return c.SetRCBValuesWithMask(objectReference, settings, mask)
+}
+
+// SetRCBValuesWithMask 写报告控制块，mask 指定写入的元素，未包含的元素保持不变。
+// ConfRev、SqNum、TimeOfEntry 和 Owner 只读；Resv 仅 URCB 可写，PurgeBuf、EntryID、ResvTms 仅 BRCB 可写
+func (c *Client) SetRCBValuesWithMask(objectReference string, settings ClientReportControlBlock, mask RCBElement) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	if mask&^writableRCBElements != 0 {
+		return ReadOnlyRCBElement
+	}
+
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 	rcb := C.ClientReportControlBlock_create(cObjectRef)
 	defer C.ClientReportControlBlock_destroy(rcb)
-	var trgOps, optFlds C.int
-	// trgOps
-	if settings.TrgOps.DataChange {
+
+	if bool(C.ClientReportControlBlock_isBuffered(rcb)) {
+		if mask&unbufferedOnlyRCBElements != 0 {
+			return UnsupportedRCBElement
+		}
+	} else if mask&bufferedOnlyRCBElements != 0 {
+		return UnsupportedRCBElement
+	}
+
+	if mask&RCB_ELEMENT_RPT_ID != 0 {
+		cRptID := Go2CStr(settings.RptID)
+		defer C.free(unsafe.Pointer(cRptID))
+		C.ClientReportControlBlock_setRptId(rcb, cRptID)
+	}
+	if mask&RCB_ELEMENT_RPT_ENA != 0 {
+		C.ClientReportControlBlock_setRptEna(rcb, C.bool(settings.Ena)) // 报告使能
+	}
+	if mask&RCB_ELEMENT_RESV != 0 {
+		C.ClientReportControlBlock_setResv(rcb, C.bool(settings.Resv))
+	}
+	if mask&RCB_ELEMENT_DATSET != 0 {
+		cDatSet := Go2CStr(settings.DatSet)
+		defer C.free(unsafe.Pointer(cDatSet))
+		C.ClientReportControlBlock_setDataSetReference(rcb, cDatSet)
+	}
+	if mask&RCB_ELEMENT_OPT_FLDS != 0 {
+		C.ClientReportControlBlock_setOptFlds(rcb, toOptFldsMask(settings.OptFlds))
+	}
+	if mask&RCB_ELEMENT_BUF_TM != 0 {
+		C.ClientReportControlBlock_setBufTm(rcb, C.uint32_t(settings.BufTm))
+	}
+	if mask&RCB_ELEMENT_TRG_OPS != 0 {
+		C.ClientReportControlBlock_setTrgOps(rcb, toTrgOpsMask(settings.TrgOps)) // 触发条件
+	}
+	if mask&RCB_ELEMENT_INTG_PD != 0 {
+		C.ClientReportControlBlock_setIntgPd(rcb, C.uint32_t(settings.IntgPd)) // 周期上送时间
+	}
+	if mask&RCB_ELEMENT_GI != 0 {
+		C.ClientReportControlBlock_setGI(rcb, C.bool(settings.GI))
+	}
+	if mask&RCB_ELEMENT_PURGE_BUF != 0 {
+		C.ClientReportControlBlock_setPurgeBuf(rcb, C.bool(settings.PurgeBuf))
+	}
+	if mask&RCB_ELEMENT_ENTRY_ID != 0 {
+		entryID := C.MmsValue_newOctetString(C.int(len(settings.EntryID)), C.int(len(settings.EntryID)))
+		defer C.MmsValue_delete(entryID)
+		if len(settings.EntryID) > 0 {
+			C.MmsValue_setOctetString(entryID, (*C.uint8_t)(unsafe.Pointer(&settings.EntryID[0])), C.int(len(settings.EntryID)))
+		}
+		C.ClientReportControlBlock_setEntryId(rcb, entryID)
+	}
+	if mask&RCB_ELEMENT_RESV_TMS != 0 {
+		C.ClientReportControlBlock_setResvTms(rcb, C.int16_t(settings.ResvTms))
+	}
+
+	C.IedConnection_setRCBValues(c.conn, &clientError, rcb, C.uint32_t(mask), true)
+	if err := newIedError("set RCB values", objectReference, NONE, clientError); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isBufferedRCB 根据引用判断是否为 BRCB，引用中 FC 为 BR
+func isBufferedRCB(objectReference string) bool {
+	cObjectRef := C.CString(objectReference)
+	defer C.free(unsafe.Pointer(cObjectRef))
+	rcb := C.ClientReportControlBlock_create(cObjectRef)
+	defer C.ClientReportControlBlock_destroy(rcb)
+	return bool(C.ClientReportControlBlock_isBuffered(rcb))
+}
+
+func toTrgOpsMask(settings TrgOps) C.int {
+	var trgOps C.int
+	if settings.DataChange {
 		trgOps = trgOps | C.TRG_OPT_DATA_CHANGED
 	}
-	if settings.TrgOps.QualityChange {
+	if settings.QualityChange {
 		trgOps = trgOps | C.TRG_OPT_QUALITY_CHANGED
 	}
-	if settings.TrgOps.DataUpdate {
+	if settings.DataUpdate {
 		trgOps = trgOps | C.TRG_OPT_DATA_UPDATE
 	}
-	if settings.TrgOps.TriggeredPeriodically {
+	if settings.TriggeredPeriodically {
 		trgOps = trgOps | C.TRG_OPT_INTEGRITY
 	}
-	if settings.TrgOps.Gi {
+	if settings.Gi {
 		trgOps = trgOps | C.TRG_OPT_GI
 	}
-	if settings.TrgOps.Transient {
+	if settings.Transient {
 		trgOps = trgOps | C.TRG_OPT_TRANSIENT
 	}
-	// optFlds
-	if settings.OptFlds.SequenceNumber {
+	return trgOps
+}
+
+func toOptFldsMask(settings OptFlds) C.int {
+	var optFlds C.int
+	if settings.SequenceNumber {
 		optFlds = optFlds | C.RPT_OPT_SEQ_NUM
 	}
-	if settings.OptFlds.TimeOfEntry {
+	if settings.TimeOfEntry {
 		optFlds = optFlds | C.RPT_OPT_TIME_STAMP
 	}
-	if settings.OptFlds.ReasonForInclusion {
+	if settings.ReasonForInclusion {
 		optFlds = optFlds | C.RPT_OPT_REASON_FOR_INCLUSION
 	}
-	if settings.OptFlds.DataSetName {
+	if settings.DataSetName {
 		optFlds = optFlds | C.RPT_OPT_DATA_SET
 	}
-	if settings.OptFlds.DataReference {
+	if settings.DataReference {
 		optFlds = optFlds | C.RPT_OPT_DATA_REFERENCE
 	}
-	if settings.OptFlds.BufferOverflow {
+	if settings.BufferOverflow {
 		optFlds = optFlds | C.RPT_OPT_BUFFER_OVERFLOW
 	}
-	if settings.OptFlds.EntryID {
+	if settings.EntryID {
 		optFlds = optFlds | C.RPT_OPT_ENTRY_ID
 	}
-	if settings.OptFlds.ConfigRevision {
+	if settings.ConfigRevision {
 		optFlds = optFlds | C.RPT_OPT_CONF_REV
 	}
-
-	C.ClientReportControlBlock_setTrgOps(rcb, trgOps)               // 触发条件
-	C.ClientReportControlBlock_setRptEna(rcb, C.bool(settings.Ena)) // 报告使能
-	C.ClientReportControlBlock_setResv(rcb, C.bool(settings.Resv))
-	C.ClientReportControlBlock_setIntgPd(rcb, C.uint32_t(settings.IntgPd)) // 周期上送时间
-	C.ClientReportControlBlock_setOptFlds(rcb, optFlds)
-
-	if bool(C.ClientReportControlBlock_isBuffered(rcb)) {
-		C.IedConnection_setRCBValues(c.conn, &clientError, rcb, C.RCB_ELEMENT_RPT_ENA|C.RCB_ELEMENT_TRG_OPS|C.RCB_ELEMENT_INTG_PD, true)
-	} else {
-		C.IedConnection_setRCBValues(c.conn, &clientError, rcb, C.RCB_ELEMENT_RESV|C.RCB_ELEMENT_RPT_ENA|C.RCB_ELEMENT_TRG_OPS|C.RCB_ELEMENT_INTG_PD, true)
-	}
-
-	if err := GetIedClientError(clientError); err != nil {
-		return err
-	}
-	return nil
+	return optFlds
 }
 
 func IsBitSet(val int, pos int) bool {
@@ -0,0 +1,142 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// readManyBatchSize 单个 MMS 读请求中最多包含的变量数，避免超过协商的 PDU 大小
+const readManyBatchSize = 64
+
+// ObjectRefFC 带功能约束的对象引用
+type ObjectRefFC struct {
+	ObjectRef string // 对象引用，如 simpleIOGenericIO/GGIO1.AnIn1.mag.f
+	FC        FC     // 功能约束
+}
+
+// ReadResult 单个变量的读取结果，读取失败时 Err 不为空
+type ReadResult struct {
+	Value *MmsValue
+	Err   error
+}
+
+// ReadMany 按逻辑设备分组，以 MMS 多变量读取一次读取多个属性，结果与 refs 一一对应。
+// 单个变量的访问错误记录在对应结果的 Err 中，某一批请求失败时该批变量的 Err 为请求错误，其他批次的结果保留；
+// 所有批次都失败或连接断开时返回错误
+func (c *Client) ReadMany(refs []ObjectRefFC) ([]ReadResult, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	results := make([]ReadResult, len(refs))
+
+	// 按逻辑设备分组，保留在 refs 中的序号
+	domains := make(map[string][]int)
+	domainOrder := make([]string, 0)
+	itemIds := make([]string, len(refs))
+	for i, ref := range refs {
+		domainId, itemId, err := toMmsVariableName(ref)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if _, ok := domains[domainId]; !ok {
+			domainOrder = append(domainOrder, domainId)
+		}
+		domains[domainId] = append(domains[domainId], i)
+		itemIds[i] = itemId
+	}
+
+	var (
+		batches   int
+		succeeded int
+		lastErr   error
+	)
+	mmsConnection := C.IedConnection_getMmsConnection(c.conn)
+	for _, domainId := range domainOrder {
+		indexes := domains[domainId]
+		for start := 0; start < len(indexes); start += readManyBatchSize {
+			end := start + readManyBatchSize
+			if end > len(indexes) {
+				end = len(indexes)
+			}
+			batches++
+			err := readMultipleVariables(mmsConnection, domainId, indexes[start:end], itemIds, results)
+			if err == nil {
+				succeeded++
+				continue
+			}
+			if isNotConnected(err) {
+				return nil, err
+			}
+			lastErr = err
+			for _, i := range indexes[start:end] {
+				results[i].Err = err
+			}
+		}
+	}
+	if batches > 0 && succeeded == 0 {
+		return nil, lastErr
+	}
+	return results, nil
+}
+
+func readMultipleVariables(mmsConnection C.MmsConnection, domainId string, indexes []int, itemIds []string, results []ReadResult) error {
+	cDomainId := C.CString(domainId)
+	defer C.free(unsafe.Pointer(cDomainId))
+
+	items := C.LinkedList_create()
+	defer C.LinkedList_destroy(items)
+	for _, i := range indexes {
+		C.LinkedList_add(items, unsafe.Pointer(C.CString(itemIds[i])))
+	}
+
+	var mmsError C.MmsError
+	values := C.MmsConnection_readMultipleVariables(mmsConnection, &mmsError, cDomainId, items)
+	if err := newMmsIedError("read many", domainId, NONE, mmsError); err != nil {
+		return err
+	}
+	if values == nil {
+		return UnexpectedValueReceived
+	}
+	defer C.MmsValue_delete(values)
+
+	for n, i := range indexes {
+		value := C.MmsValue_getElement(values, C.int(n))
+		if value == nil {
+			results[i].Err = UnexpectedValueReceived
+			continue
+		}
+		mmsType := MmsType(C.MmsValue_getType(value))
+		goValue, err := toGoValue(value, mmsType)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Value = &MmsValue{Type: mmsType, Value: goValue}
+	}
+	return nil
+}
+
+// toMmsVariableName 将 LD/LN.DO.DA 形式的引用转换为 MMS 域名和变量名 LN$FC$DO$DA
+func toMmsVariableName(ref ObjectRefFC) (string, string, error) {
+	domainId, path, ok := strings.Cut(ref.ObjectRef, "/")
+	if !ok || domainId == "" || path == "" {
+		return "", "", fmt.Errorf("%w: %s", ObjectReferenceInvalid, ref.ObjectRef)
+	}
+	fc := ref.FC.String()
+	if fc == "" {
+		return "", "", fmt.Errorf("%w: %s", UserProvidedInvalidArgument, ref.ObjectRef)
+	}
+
+	ln, rest, hasRest := strings.Cut(path, ".")
+	itemId := ln + "$" + fc
+	if hasRest {
+		itemId += "$" + strings.ReplaceAll(rest, ".", "$")
+	}
+	return domainId, itemId, nil
+}
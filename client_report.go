@@ -7,6 +7,7 @@ extern void reportCallbackFunctionBridge(void* parameter, ClientReport report);
 */
 import "C"
 import (
+	"fmt"
 	"sync"
 	"unsafe"
 )
@@ -159,48 +160,127 @@ func (clientReport *ClientReport) GetMoreSeqmentsFollow() bool {
 	return bool(C.ClientReport_getMoreSeqmentsFollow(clientReport.Report))
 }
 
+type installedReportHandler struct {
+	handler    ReportCallbackFunction
+	callbackId int32
+}
+
+// InstallReportHandler 注册报告回调，自动重连的客户端会在重连后重新注册；
+// 若自动重连的客户端当前未连接，回调会在连接建立后注册。
+// 控制块已有缓存报告订阅时返回 ReportHandlerExists，避免替换掉订阅的回调
 func (c *Client) InstallReportHandler(objectReference string, function ReportCallbackFunction) error {
+	c.mu.RLock()
+	_, subscribed := c.bufferedReports[objectReference]
+	c.mu.RUnlock()
+	if subscribed {
+		return fmt.Errorf("%w: %s", ReportHandlerExists, objectReference)
+	}
+	return c.setReportHandler(objectReference, function)
+}
+
+// setReportHandler 注册或替换报告回调
+func (c *Client) setReportHandler(objectReference string, function ReportCallbackFunction) error {
+	callbackId := callbackIdGen.Add(1)
+	reportCallbacks.Store(callbackId, &reportCallbackHandler{
+		handler: function,
+	})
+	installed := &installedReportHandler{
+		handler:    function,
+		callbackId: callbackId,
+	}
+
+	// 读取 RCB 是网络请求，不持有 c.mu，避免阻塞状态回调和其他调用
+	err := c.installReportHandler(objectReference, installed)
+	if err != nil && !(c.supervisor != nil && isNotConnected(err)) {
+		reportCallbacks.Delete(callbackId)
+		return err
+	}
+
+	c.mu.Lock()
+	if previous, ok := c.reportHandlers[objectReference]; ok {
+		reportCallbacks.Delete(previous.callbackId)
+	}
+	c.reportHandlers[objectReference] = installed
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) installReportHandler(objectReference string, installed *installedReportHandler) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	rcb := C.IedConnection_getRCBValues(c.conn, &clientError, cObjectRef, nil)
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("install report handler", objectReference, NONE, clientError); err != nil {
 		return err
 	}
 	defer C.ClientReportControlBlock_destroy(rcb)
 
-	callbackId := callbackIdGen.Add(1)
-	reportCallbacks.Store(callbackId, &reportCallbackHandler{
-		handler: function,
-	})
-
-	C.IedConnection_installReportHandler(c.conn, cObjectRef, C.ClientReportControlBlock_getRptId(rcb), (*[0]byte)(C.reportCallbackFunctionBridge), intToPointerBug58625(callbackId))
+	C.IedConnection_uninstallReportHandler(c.conn, cObjectRef)
+	C.IedConnection_installReportHandler(c.conn, cObjectRef, C.ClientReportControlBlock_getRptId(rcb), (*[0]byte)(C.reportCallbackFunctionBridge), intToPointerBug58625(installed.callbackId))
 
 	return nil
 }
 
+// reinstallReportHandlers 重连后重新注册所有报告回调
+func (c *Client) reinstallReportHandlers() {
+	c.mu.RLock()
+	handlers := make(map[string]*installedReportHandler, len(c.reportHandlers))
+	for objectReference, installed := range c.reportHandlers {
+		handlers[objectReference] = installed
+	}
+	c.mu.RUnlock()
+
+	for objectReference, installed := range handlers {
+		// 失败的回调在下次重连时重试
+		_ = c.installReportHandler(objectReference, installed)
+	}
+}
+
 func (c *Client) UninstallReportHandler(objectReference string) {
+	c.mu.Lock()
+	if installed, ok := c.reportHandlers[objectReference]; ok {
+		reportCallbacks.Delete(installed.callbackId)
+		delete(c.reportHandlers, objectReference)
+	}
+	c.mu.Unlock()
+
+	// 客户端关闭时回调已经随连接一起注销
+	if c.acquire() != nil {
+		return
+	}
+	defer c.release()
+
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 	C.IedConnection_uninstallReportHandler(c.conn, cObjectRef)
 }
 
 func (c *Client) TriggerGIReport(objectReference string) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	rcb := C.IedConnection_getRCBValues(c.conn, &clientError, cObjectRef, nil)
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("trigger GI report", objectReference, NONE, clientError); err != nil {
 		return err
 	}
 	defer C.ClientReportControlBlock_destroy(rcb)
 
 	C.IedConnection_triggerGIReport(c.conn, &clientError, cObjectRef)
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("trigger GI report", objectReference, NONE, clientError); err != nil {
 		return err
 	}
 
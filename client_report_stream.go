@@ -0,0 +1,234 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReportOverflowPolicy 报告通道已满时的处理策略
+type ReportOverflowPolicy int
+
+const (
+	REPORT_OVERFLOW_DROP_OLDEST ReportOverflowPolicy = iota // 丢弃通道中最早的报告
+	REPORT_OVERFLOW_DROP_NEWEST                             // 丢弃新收到的报告
+	REPORT_OVERFLOW_BLOCK                                   // 阻塞 libiec61850 接收线程直到通道有空间
+)
+
+// ReportStreamSettings 报告通道配置
+type ReportStreamSettings struct {
+	BufferSize     int                  // 通道缓冲区大小
+	OverflowPolicy ReportOverflowPolicy // 通道已满时的处理策略
+}
+
+func NewReportStreamSettings() ReportStreamSettings {
+	return ReportStreamSettings{
+		BufferSize:     64,
+		OverflowPolicy: REPORT_OVERFLOW_DROP_OLDEST,
+	}
+}
+
+// Report 报告快照，所有字段都已从 C 内存中复制，可在回调之外使用
+type Report struct {
+	RcbReference       string         // 报告控制块引用
+	RptID              string         // 报告标识
+	HasSeqNum          bool           // 是否包含顺序号
+	SeqNum             uint16         // 顺序号
+	HasSubSeqNum       bool           // 是否分段
+	SubSeqNum          uint16         // 分段顺序号
+	MoreSegmentsFollow bool           // 后续还有分段
+	HasTimestamp       bool           // 是否包含报告时标
+	Timestamp          time.Time      // 报告时标
+	EntryID            []byte         // 条目标识符，仅 BRCB
+	HasConfRev         bool           // 是否包含配置版本号
+	ConfRev            uint32         // 配置版本号
+	DataSetName        string         // 数据集，报告中不包含时为空
+	BufOvfl            bool           // 缓存溢出，仅 BRCB
+	Members            []ReportMember // 报告包含的数据集成员
+}
+
+// ReportMember 报告中的单个数据集成员
+type ReportMember struct {
+	Index         int                // 在数据集中的序号
+	DataReference string             // 数据引用，报告中不包含时为空
+	Value         *MmsValue          // 数据值，解码失败时为 nil
+	ReasonCode    ReasonForInclusion // 原因码，报告中不包含时为 IEC61850_REASON_NOT_INCLUDED
+	Err           error              // 数据值解码失败的原因
+}
+
+type reportStream struct {
+	ch      chan Report
+	policy  ReportOverflowPolicy
+	done    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// SubscribeReports 注册报告回调并返回报告通道，每个报告都在 libiec61850 线程中解码为 Report 快照后送入通道。
+// UnsubscribeReports 或客户端 Close 后通道关闭。同一控制块再次订阅时，之前的通道会被关闭
+func (c *Client) SubscribeReports(rcbReference string, settings ReportStreamSettings) (<-chan Report, error) {
+	if settings.BufferSize < 0 {
+		return nil, UserProvidedInvalidArgument
+	}
+
+	stream := &reportStream{
+		ch:     make(chan Report, settings.BufferSize),
+		policy: settings.OverflowPolicy,
+		done:   make(chan struct{}),
+	}
+
+	err := c.InstallReportHandler(rcbReference, func(clientReport ClientReport) {
+		stream.deliver(toReport(clientReport))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	previous := c.reportStreams[rcbReference]
+	c.reportStreams[rcbReference] = stream
+	c.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+	return stream.ch, nil
+}
+
+// UnsubscribeReports 取消报告订阅并关闭通道
+func (c *Client) UnsubscribeReports(rcbReference string) {
+	c.mu.Lock()
+	stream := c.reportStreams[rcbReference]
+	delete(c.reportStreams, rcbReference)
+	c.mu.Unlock()
+
+	// 没有订阅时不注销回调，避免注销其他方式注册的回调
+	if stream != nil {
+		c.UninstallReportHandler(rcbReference)
+		stream.close()
+	}
+}
+
+// DroppedReports 返回因通道已满而丢弃的报告数量
+func (c *Client) DroppedReports(rcbReference string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if stream, ok := c.reportStreams[rcbReference]; ok {
+		return stream.dropped.Load()
+	}
+	return 0
+}
+
+func (s *reportStream) deliver(report Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	switch s.policy {
+	case REPORT_OVERFLOW_BLOCK:
+		select {
+		case s.ch <- report:
+		case <-s.done:
+		}
+	case REPORT_OVERFLOW_DROP_NEWEST:
+		select {
+		case s.ch <- report:
+		default:
+			s.dropped.Add(1)
+		}
+	default:
+		for {
+			select {
+			case s.ch <- report:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+				// 无缓冲通道且没有接收方
+				if cap(s.ch) == 0 {
+					s.dropped.Add(1)
+					return
+				}
+			}
+		}
+	}
+}
+
+// close 先通知阻塞中的 deliver 返回，再关闭通道
+func (s *reportStream) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
+
+// toReport 将 C 报告复制为 Report 快照
+func toReport(clientReport ClientReport) Report {
+	report := Report{
+		RcbReference:       clientReport.GetRcbReference(),
+		RptID:              clientReport.GetRptId(),
+		HasSeqNum:          clientReport.HasSeqNum(),
+		SeqNum:             uint16(C.ClientReport_getSeqNum(clientReport.Report)),
+		HasSubSeqNum:       clientReport.HasSubSeqNum(),
+		SubSeqNum:          uint16(C.ClientReport_getSubSeqNum(clientReport.Report)),
+		MoreSegmentsFollow: clientReport.GetMoreSeqmentsFollow(),
+		HasTimestamp:       clientReport.HasTimestamp(),
+		EntryID:            toOctetString(C.ClientReport_getEntryId(clientReport.Report)),
+		HasConfRev:         clientReport.HasConfRev(),
+		ConfRev:            uint32(C.ClientReport_getConfRev(clientReport.Report)),
+	}
+	if report.HasTimestamp {
+		report.Timestamp = time.UnixMilli(clientReport.GetTimestamp())
+	}
+	if clientReport.HasDataSetName() {
+		report.DataSetName = clientReport.GetDataSetName()
+	}
+	if clientReport.HasBufOvfl() {
+		report.BufOvfl = clientReport.GetBufOvfl()
+	}
+
+	dataSetValues := C.ClientReport_getDataSetValues(clientReport.Report)
+	if dataSetValues == nil {
+		return report
+	}
+
+	hasReason := clientReport.HasReasonForInclusion()
+	hasDataReference := clientReport.HasDataReference()
+	size := int(C.MmsValue_getArraySize(dataSetValues))
+	for i := 0; i < size; i++ {
+		reason := IEC61850_REASON_NOT_INCLUDED
+		if hasReason {
+			// 未包含在本次报告中的成员保留的是旧值
+			if reason = clientReport.GetReasonForInclusion(i); reason == IEC61850_REASON_NOT_INCLUDED {
+				continue
+			}
+		}
+
+		member := ReportMember{
+			Index:      i,
+			ReasonCode: reason,
+		}
+		if hasDataReference {
+			member.DataReference = clientReport.GetDataReference(i)
+		}
+		if value, err := clientReport.GetElement(i); err != nil {
+			member.Err = err
+		} else {
+			member.Value = &value
+		}
+		report.Members = append(report.Members, member)
+	}
+	return report
+}
@@ -4,8 +4,11 @@ package iec61850
 import "C"
 import (
 	"fmt"
-	"github.com/spf13/cast"
+	"strings"
+	"time"
 	"unsafe"
+
+	"github.com/spf13/cast"
 )
 
 const (
@@ -15,10 +18,19 @@ const (
 )
 
 type SettingGroup struct {
-	NumOfSG int
-	ActSG   int
-	EditSG  int
-	CnfEdit bool
+	NumOfSG    int
+	ActSG      int
+	EditSG     int
+	CnfEdit    bool
+	LActTm     time.Time // 最后一次切换激活定值组的时间
+	HasResvTms bool      // 服务端是否有 ResvTms
+	ResvTms    int       // 编辑定值组的保留时间，秒
+}
+
+// SettingValue 单个定值，ObjectRef 为 SE 或 SG 功能约束下的数据属性引用
+type SettingValue struct {
+	ObjectRef string    `json:"ref"`
+	Value     *MmsValue `json:"value"`
 }
 
 // WriteSG 写入SettingGroup
@@ -47,23 +59,28 @@ func (c *Client) WriteSG(ld, ln, objectRef string, fc FC, actSG int, value inter
 
 // GetSG 获取SettingGroup
 func (c *Client) GetSG(objectRef string) (*SettingGroup, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectRef)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
 	// 获取类型
 	sgcbVarSpec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(SP))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, SP, clientError); err != nil {
 		return nil, err
 	}
 	defer C.MmsVariableSpecification_destroy(sgcbVarSpec)
 
 	// Read SGCB
 	sgcbVal := C.IedConnection_readObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(SP))
-	if err := GetIedClientError(clientError); err != nil {
+	if err := newIedError("read", objectRef, SP, clientError); err != nil {
 		return nil, err
 	}
-	//defer C.MmsValue_delete(sgcbVal)
+	defer C.MmsValue_delete(sgcbVal)
 
 	numOfSGValue, err := c.getSubElementValue(sgcbVal, sgcbVarSpec, "NumOfSG")
 	if err != nil {
@@ -91,5 +108,159 @@ func (c *Client) GetSG(objectRef string) (*SettingGroup, error) {
 		EditSG:  cast.ToInt(editSGValue),
 		CnfEdit: cast.ToBool(cnfEditValue),
 	}
+
+	lActTm, err := c.getSubElement(sgcbVal, sgcbVarSpec, "LActTm")
+	if err != nil {
+		return nil, err
+	}
+	if MmsType(C.MmsValue_getType(lActTm)) != UTCTime {
+		return nil, fmt.Errorf("%w: LActTm is not a UtcTime", UnexpectedValueReceived)
+	}
+	sg.LActTm = time.UnixMilli(int64(C.MmsValue_getUtcTimeInMs(lActTm)))
+
+	// ResvTms 为可选属性
+	if resvTmsValue, err := c.getSubElementValue(sgcbVal, sgcbVarSpec, "ResvTms"); err == nil {
+		sg.HasResvTms = true
+		sg.ResvTms = cast.ToInt(resvTmsValue)
+	}
 	return sg, nil
 }
+
+// GetSGCBReference 从逻辑设备 ld 的变量中查找定值组控制块，返回如 LD/LLN0.SGCB 的引用，没有时返回 ObjectDoesNotExist。
+// 查找结果按逻辑设备缓存，之后的定值组服务不再读取变量列表
+func (c *Client) GetSGCBReference(ld string) (string, error) {
+	if sgcb, ok := c.sgcbReferences.Load(ld); ok {
+		return sgcb.(string), nil
+	}
+	variables, err := c.GetLogicalDeviceVariables(ld)
+	if err != nil {
+		return "", err
+	}
+	return c.findSGCBReference(ld, variables)
+}
+
+// findSGCBReference 从已读取的变量列表中查找定值组控制块并缓存
+func (c *Client) findSGCBReference(ld string, variables []string) (string, error) {
+	for _, variable := range variables {
+		if ln, ok := strings.CutSuffix(variable, "$SP$SGCB"); ok && !strings.Contains(ln, "$") {
+			sgcb := ld + "/" + ln + ".SGCB"
+			c.sgcbReferences.Store(ld, sgcb)
+			return sgcb, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s has no SGCB", ObjectDoesNotExist, ld)
+}
+
+// SelectActiveSG 切换逻辑设备 ld 的激活定值组，sg 从 1 开始
+func (c *Client) SelectActiveSG(ld string, sg int) error {
+	sgcb, err := c.GetSGCBReference(ld)
+	if err != nil {
+		return err
+	}
+	return c.Write(sgcb+".ActSG", SP, sg)
+}
+
+// SelectEditSG 选择逻辑设备 ld 的编辑定值组，开始一次编辑会话。sg 为 0 时释放编辑会话且不保存
+func (c *Client) SelectEditSG(ld string, sg int) error {
+	sgcb, err := c.GetSGCBReference(ld)
+	if err != nil {
+		return err
+	}
+	return c.selectEditSG(sgcb, sg)
+}
+
+// ReadEditSG 读取逻辑设备 ld 中当前编辑定值组的所有 SE 定值，调用前需要 SelectEditSG
+func (c *Client) ReadEditSG(ld string) ([]SettingValue, error) {
+	return c.readSettings(ld, SE)
+}
+
+// SetEditSGValues 在当前编辑会话中写入多个定值，写入的值在 ConfirmEditSG 后才生效。
+// 任意一个定值写入失败时停止并返回错误，已写入的值仍在编辑会话中，可以 SelectEditSG(ld, 0) 放弃
+func (c *Client) SetEditSGValues(ld string, values []SettingValue) error {
+	for _, value := range values {
+		if !strings.HasPrefix(value.ObjectRef, ld+"/") {
+			return fmt.Errorf("%w: %s is not in %s", ObjectReferenceInvalid, value.ObjectRef, ld)
+		}
+		if err := c.Write(value.ObjectRef, SE, value.Value); err != nil {
+			return fmt.Errorf("write %s: %w", value.ObjectRef, err)
+		}
+	}
+	return nil
+}
+
+// ConfirmEditSG 确认编辑会话，服务端将编辑的定值一次性保存到编辑定值组
+func (c *Client) ConfirmEditSG(ld string) error {
+	sgcb, err := c.GetSGCBReference(ld)
+	if err != nil {
+		return err
+	}
+	return c.confirmEditSG(sgcb)
+}
+
+func (c *Client) selectEditSG(sgcb string, sg int) error {
+	return c.Write(sgcb+".EditSG", SP, sg)
+}
+
+func (c *Client) confirmEditSG(sgcb string) error {
+	return c.Write(sgcb+".CnfEdit", SP, true)
+}
+
+// readSettings 按功能约束读取逻辑设备中的所有叶子数据属性
+func (c *Client) readSettings(ld string, fc FC) ([]SettingValue, error) {
+	variables, err := c.GetLogicalDeviceVariables(ld)
+	if err != nil {
+		return nil, err
+	}
+	return c.readSettingLeaves(ld, variables, fc)
+}
+
+// readSettingLeaves 按已读取的变量列表读取指定功能约束的叶子数据属性
+func (c *Client) readSettingLeaves(ld string, variables []string, fc FC) ([]SettingValue, error) {
+	refs := make([]ObjectRefFC, 0)
+	for _, name := range settingLeaves(variables, fc) {
+		refs = append(refs, ObjectRefFC{ObjectRef: ld + "/" + name, FC: fc})
+	}
+
+	results, err := c.ReadMany(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make([]SettingValue, 0, len(refs))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("read %s: %w", refs[i].ObjectRef, result.Err)
+		}
+		settings = append(settings, SettingValue{ObjectRef: refs[i].ObjectRef, Value: result.Value})
+	}
+	return settings, nil
+}
+
+// settingLeaves 从 MMS 变量名（LN$FC$DO$DA）中选出指定功能约束的叶子属性，返回 LN.DO.DA 形式
+func settingLeaves(variables []string, fc FC) []string {
+	infix := "$" + fc.String() + "$"
+	names := make([]string, 0)
+	for _, variable := range variables {
+		if strings.Contains(variable, infix) {
+			names = append(names, variable)
+		}
+	}
+
+	// 有子元素的变量不是叶子
+	parents := make(map[string]bool)
+	for _, name := range names {
+		if i := strings.LastIndex(name, "$"); i > 0 {
+			parents[name[:i]] = true
+		}
+	}
+
+	leaves := make([]string, 0, len(names))
+	for _, name := range names {
+		if parents[name] {
+			continue
+		}
+		ln, rest, _ := strings.Cut(name, infix)
+		leaves = append(leaves, ln+"."+strings.ReplaceAll(rest, "$", "."))
+	}
+	return leaves
+}
@@ -0,0 +1,282 @@
+package iec61850
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SettingGroups 逻辑设备所有定值组的定值快照，可保存为 JSON 或 CSV 进行版本管理
+type SettingGroups struct {
+	LD     string               `json:"ld"`
+	ActSG  int                  `json:"actSG"`
+	Groups []SettingGroupValues `json:"groups"`
+}
+
+// SettingGroupValues 单个定值组的定值
+type SettingGroupValues struct {
+	SG     int            `json:"sg"`
+	Values []SettingValue `json:"values"`
+}
+
+// SettingDiff 定值差异，Old 或 New 为 nil 表示该定值只存在于一侧
+type SettingDiff struct {
+	SG        int       `json:"sg"`
+	ObjectRef string    `json:"ref"`
+	Old       *MmsValue `json:"old,omitempty"`
+	New       *MmsValue `json:"new,omitempty"`
+}
+
+func (d SettingDiff) String() string {
+	return fmt.Sprintf("SG%d %s: %s -> %s", d.SG, d.ObjectRef, settingValueString(d.Old), settingValueString(d.New))
+}
+
+// ExportSettingGroups 依次选择每个编辑定值组并读取其所有 SE 定值，完成后释放编辑会话，释放失败时返回错误。
+// 逻辑设备的变量列表只读取一次，用于查找定值组控制块和各定值组的定值
+func (c *Client) ExportSettingGroups(ld string) (*SettingGroups, error) {
+	groups, _, err := c.exportSettingGroups(ld)
+	return groups, err
+}
+
+// exportSettingGroups 返回快照和定值组控制块引用
+func (c *Client) exportSettingGroups(ld string) (groups *SettingGroups, sgcb string, err error) {
+	variables, err := c.GetLogicalDeviceVariables(ld)
+	if err != nil {
+		return nil, "", err
+	}
+	if sgcb, err = c.findSGCBReference(ld, variables); err != nil {
+		return nil, "", err
+	}
+	sg, err := c.GetSG(sgcb)
+	if err != nil {
+		return nil, "", err
+	}
+
+	groups = &SettingGroups{LD: ld, ActSG: sg.ActSG}
+	defer func() {
+		// 编辑会话未释放时其他客户端无法编辑，释放失败也要返回错误
+		if releaseErr := c.selectEditSG(sgcb, 0); releaseErr != nil && err == nil {
+			groups, err = nil, fmt.Errorf("release edit SG: %w", releaseErr)
+		}
+	}()
+	for i := 1; i <= sg.NumOfSG; i++ {
+		if err = c.selectEditSG(sgcb, i); err != nil {
+			return nil, "", fmt.Errorf("select edit SG%d: %w", i, err)
+		}
+		values, err := c.readSettingLeaves(ld, variables, SE)
+		if err != nil {
+			return nil, "", fmt.Errorf("read SG%d: %w", i, err)
+		}
+		groups.Groups = append(groups.Groups, SettingGroupValues{SG: i, Values: values})
+	}
+	return groups, sgcb, nil
+}
+
+// DiffSettingGroups 比较两份快照，返回从 from 到 to 的差异，按定值组和 to 中的顺序排列
+func DiffSettingGroups(from, to *SettingGroups) []SettingDiff {
+	diffs := make([]SettingDiff, 0)
+	fromGroups := from.groupMap()
+	toGroups := to.groupMap()
+
+	for _, group := range to.Groups {
+		old := fromGroups[group.SG]
+		for _, value := range group.Values {
+			if oldValue, ok := old[value.ObjectRef]; !ok {
+				diffs = append(diffs, SettingDiff{SG: group.SG, ObjectRef: value.ObjectRef, New: value.Value})
+			} else if settingValueString(oldValue) != settingValueString(value.Value) {
+				diffs = append(diffs, SettingDiff{SG: group.SG, ObjectRef: value.ObjectRef, Old: oldValue, New: value.Value})
+			}
+		}
+	}
+
+	for _, group := range from.Groups {
+		current := toGroups[group.SG]
+		for _, value := range group.Values {
+			if _, ok := current[value.ObjectRef]; !ok {
+				diffs = append(diffs, SettingDiff{SG: group.SG, ObjectRef: value.ObjectRef, Old: value.Value})
+			}
+		}
+	}
+	return diffs
+}
+
+// ApplySettingGroups 将快照与服务端当前定值比较，每个有差异的定值组在一次编辑会话中写入并确认。
+// dryRun 为 true 时只返回差异不写入，但读取服务端定值时仍会依次选择编辑定值组，占用编辑会话。只存在于服务端的定值不会被删除
+func (c *Client) ApplySettingGroups(groups *SettingGroups, dryRun bool) ([]SettingDiff, error) {
+	live, sgcb, err := c.exportSettingGroups(groups.LD)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]SettingDiff, 0)
+	changes := make(map[int][]SettingValue)
+	sgs := make([]int, 0)
+	for _, diff := range DiffSettingGroups(live, groups) {
+		if diff.New == nil {
+			continue
+		}
+		diffs = append(diffs, diff)
+		if _, ok := changes[diff.SG]; !ok {
+			sgs = append(sgs, diff.SG)
+		}
+		changes[diff.SG] = append(changes[diff.SG], SettingValue{ObjectRef: diff.ObjectRef, Value: diff.New})
+	}
+	if dryRun {
+		return diffs, nil
+	}
+
+	for _, sg := range sgs {
+		if err = c.applySettingGroup(groups.LD, sgcb, sg, changes[sg]); err != nil {
+			return diffs, fmt.Errorf("apply SG%d: %w", sg, err)
+		}
+	}
+	return diffs, nil
+}
+
+func (c *Client) applySettingGroup(ld, sgcb string, sg int, values []SettingValue) error {
+	if err := c.selectEditSG(sgcb, sg); err != nil {
+		return err
+	}
+	if err := c.SetEditSGValues(ld, values); err != nil {
+		// 放弃本次编辑会话
+		_ = c.selectEditSG(sgcb, 0)
+		return err
+	}
+	return c.confirmEditSG(sgcb)
+}
+
+// WriteJSON 以 JSON 格式写出快照
+func (g *SettingGroups) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// ReadSettingGroupsJSON 读取 JSON 格式的快照
+func ReadSettingGroupsJSON(r io.Reader) (*SettingGroups, error) {
+	groups := &SettingGroups{}
+	if err := json.NewDecoder(r).Decode(groups); err != nil {
+		return nil, err
+	}
+	for _, group := range groups.Groups {
+		for _, value := range group.Values {
+			if err := normalizeSettingValue(value.Value); err != nil {
+				return nil, fmt.Errorf("%s: %w", value.ObjectRef, err)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// WriteCSV 以 CSV 格式写出快照，列为 sg,ref,type,value，value 为 JSON 编码的值
+func (g *SettingGroups) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"sg", "ref", "type", "value"}); err != nil {
+		return err
+	}
+	for _, group := range g.Groups {
+		for _, value := range group.Values {
+			if value.Value == nil {
+				return fmt.Errorf("%w: %s has no value", UserProvidedInvalidArgument, value.ObjectRef)
+			}
+			data, err := json.Marshal(value.Value.Value)
+			if err != nil {
+				return err
+			}
+			record := []string{strconv.Itoa(group.SG), value.ObjectRef, value.Value.Type.String(), string(data)}
+			if err = writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadSettingGroupsCSV 读取 CSV 格式的快照，LD 取自定值引用，ActSG 为 0
+func ReadSettingGroupsCSV(r io.Reader) (*SettingGroups, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: empty settings file", UserProvidedInvalidArgument)
+	}
+
+	groups := &SettingGroups{}
+	index := make(map[int]int)
+	for line, record := range records[1:] {
+		if len(record) != 4 {
+			return nil, fmt.Errorf("line %d: %w: expected 4 columns", line+2, UserProvidedInvalidArgument)
+		}
+		sg, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		value := &MmsValue{}
+		if err = value.Type.UnmarshalText([]byte(record[2])); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		if err = json.Unmarshal([]byte(record[3]), &value.Value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		if err = normalizeSettingValue(value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+
+		if groups.LD == "" {
+			groups.LD, _, _ = strings.Cut(record[1], "/")
+		}
+		i, ok := index[sg]
+		if !ok {
+			i = len(groups.Groups)
+			index[sg] = i
+			groups.Groups = append(groups.Groups, SettingGroupValues{SG: sg})
+		}
+		groups.Groups[i].Values = append(groups.Groups[i].Values, SettingValue{ObjectRef: record[1], Value: value})
+	}
+	return groups, nil
+}
+
+func (g *SettingGroups) groupMap() map[int]map[string]*MmsValue {
+	groups := make(map[int]map[string]*MmsValue)
+	for _, group := range g.Groups {
+		values := make(map[string]*MmsValue)
+		for _, value := range group.Values {
+			values[value.ObjectRef] = value.Value
+		}
+		groups[group.SG] = values
+	}
+	return groups
+}
+
+// normalizeSettingValue JSON 中的八位位组串为 base64 字符串，还原为 []byte 以便写入
+func normalizeSettingValue(value *MmsValue) error {
+	if value == nil || value.Type != OctetString {
+		return nil
+	}
+	if s, ok := value.Value.(string); ok {
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		value.Value = data
+	}
+	return nil
+}
+
+// settingValueString 以类型和 JSON 编码比较定值，从文件读取的 float64 与服务端读取的 float32 编码相同
+func settingValueString(value *MmsValue) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value.Value)
+	if err != nil {
+		return fmt.Sprintf("%s(%v)", value.Type, value.Value)
+	}
+	return fmt.Sprintf("%s(%s)", value.Type, data)
+}
@@ -0,0 +1,369 @@
+package iec61850
+
+// #include <iec61850_client.h>
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// structTag 结构体字段标签名，如 `iec61850:"stVal"`，加上 optional 选项时服务端可以没有该成员，
+// 如 `iec61850:"stSeld,optional"`，"-" 表示忽略该字段
+const structTag = "iec61850"
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	timestampType      = reflect.TypeOf(Timestamp{})
+	qualityType        = reflect.TypeOf(Quality(0))
+	bitStringValueType = reflect.TypeOf(BitStringValue{})
+	mmsValueType       = reflect.TypeOf(MmsValue{})
+)
+
+// ReadInto 读取结构体类型的属性并按 iec61850 标签填充到 v，v 必须是指针。
+// 服务端结构体成员没有对应字段，或者非 optional 字段在服务端不存在时返回错误
+func (c *Client) ReadInto(objectRef string, fc FC, v interface{}) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return UserProvidedInvalidArgument
+	}
+
+	var clientError C.IedClientError
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	mmsValue := C.IedConnection_readObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("read", objectRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	return decodeMmsValue(spec, mmsValue, rv.Elem())
+}
+
+// WriteFrom 按 iec61850 标签将 v 转换为结构体类型的值并写入
+func (c *Client) WriteFrom(objectRef string, fc FC, v interface{}) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	var clientError C.IedClientError
+	cObjectRef := C.CString(objectRef)
+	defer C.free(unsafe.Pointer(cObjectRef))
+
+	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
+	if err := newIedError("write", objectRef, fc, clientError); err != nil {
+		return err
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+
+	mmsValue, err := encodeMmsValue(spec, reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	C.IedConnection_writeObject(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc), mmsValue)
+	return newIedError("write", objectRef, fc, clientError)
+}
+
+// structFields 返回类型规格中每个成员对应的字段，找不到对应字段时返回错误
+func structFields(spec *C.MmsVariableSpecification, rv reflect.Value) ([]reflect.Value, error) {
+	type taggedField struct {
+		value    reflect.Value
+		optional bool
+		used     bool
+	}
+
+	tagged := make(map[string]*taggedField)
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok := rt.Field(i).Tag.Lookup(structTag)
+		if !ok || tag == "-" || !rt.Field(i).IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		tagged[name] = &taggedField{value: rv.Field(i), optional: options == "optional"}
+	}
+
+	size := int(C.MmsVariableSpecification_getSize(spec))
+	fields := make([]reflect.Value, size)
+	for i := 0; i < size; i++ {
+		name := C.GoString(C.MmsVariableSpecification_getName(C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))))
+		field, ok := tagged[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no field tagged %q", StructureComponentUnmapped, rt, name)
+		}
+		field.used = true
+		fields[i] = field.value
+	}
+
+	for name, field := range tagged {
+		if !field.used && !field.optional {
+			return nil, fmt.Errorf("%w: %s", StructureComponentMissing, name)
+		}
+	}
+	return fields, nil
+}
+
+func decodeMmsValue(spec *C.MmsVariableSpecification, mmsValue *C.MmsValue, rv reflect.Value) error {
+	mmsType := MmsType(C.MmsValue_getType(mmsValue))
+	if mmsType == DataAccessError {
+		return MmsDataAccessError(C.MmsValue_getDataAccessError(mmsValue))
+	}
+
+	switch rv.Type() {
+	case mmsValueType:
+		goValue, err := toGoValue(mmsValue, mmsType)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(MmsValue{Type: mmsType, Value: goValue}))
+		return nil
+	case qualityType:
+		if mmsType != BitString {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.SetUint(uint64(C.MmsValue_getBitStringAsInteger(mmsValue)))
+		return nil
+	case bitStringValueType:
+		if mmsType != BitString {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.Set(reflect.ValueOf(BitStringValue{
+			Size:  int(C.MmsValue_getBitStringSize(mmsValue)),
+			Value: uint32(C.MmsValue_getBitStringAsInteger(mmsValue)),
+		}))
+		return nil
+	case timestampType:
+		if mmsType != UTCTime {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.Set(reflect.ValueOf(toTimestamp(mmsValue)))
+		return nil
+	case timeType:
+		switch mmsType {
+		case UTCTime:
+			rv.Set(reflect.ValueOf(time.UnixMilli(int64(C.MmsValue_getUtcTimeInMs(mmsValue)))))
+		case BinaryTime:
+			rv.Set(reflect.ValueOf(time.UnixMilli(int64(C.MmsValue_getBinaryTimeAsUtcMs(mmsValue)))))
+		default:
+			return decodeMismatch(mmsType, rv)
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeMmsValue(spec, mmsValue, rv.Elem())
+	case reflect.Interface:
+		goValue, err := toGoValue(mmsValue, mmsType)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(goValue))
+		return nil
+	}
+
+	switch mmsType {
+	case Structure:
+		if rv.Kind() != reflect.Struct {
+			return decodeMismatch(mmsType, rv)
+		}
+		fields, err := structFields(spec, rv)
+		if err != nil {
+			return err
+		}
+		for i, field := range fields {
+			childSpec := C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))
+			if err = decodeMmsValue(childSpec, C.MmsValue_getElement(mmsValue, C.int(i)), field); err != nil {
+				return fmt.Errorf("%s: %w", C.GoString(C.MmsVariableSpecification_getName(childSpec)), err)
+			}
+		}
+	case Array:
+		size := int(C.MmsValue_getArraySize(mmsValue))
+		switch rv.Kind() {
+		case reflect.Slice:
+			rv.Set(reflect.MakeSlice(rv.Type(), size, size))
+		case reflect.Array:
+			if rv.Len() != size {
+				return fmt.Errorf("%w: expected %d elements, got %d", ElementCountMismatch, rv.Len(), size)
+			}
+		default:
+			return decodeMismatch(mmsType, rv)
+		}
+		elementSpec := C.MmsVariableSpecification_getArrayElementSpecification(spec)
+		for i := 0; i < size; i++ {
+			if err := decodeMmsValue(elementSpec, C.MmsValue_getElement(mmsValue, C.int(i)), rv.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case Boolean:
+		if rv.Kind() != reflect.Bool {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.SetBool(bool(C.MmsValue_getBoolean(mmsValue)))
+	case Integer, Unsigned:
+		var i int64
+		if mmsType == Integer {
+			i = int64(C.MmsValue_toInt64(mmsValue))
+		} else {
+			i = int64(C.MmsValue_toUint32(mmsValue))
+		}
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.OverflowInt(i) {
+				return fmt.Errorf("%w: %d overflows %s", TypeInconsistent, i, rv.Type())
+			}
+			rv.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if i < 0 || rv.OverflowUint(uint64(i)) {
+				return fmt.Errorf("%w: %d overflows %s", TypeInconsistent, i, rv.Type())
+			}
+			rv.SetUint(uint64(i))
+		default:
+			return decodeMismatch(mmsType, rv)
+		}
+	case Float:
+		switch rv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			rv.SetFloat(float64(C.MmsValue_toDouble(mmsValue)))
+		default:
+			return decodeMismatch(mmsType, rv)
+		}
+	case VisibleString, String:
+		if rv.Kind() != reflect.String {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.SetString(C.GoString(C.MmsValue_toString(mmsValue)))
+	case OctetString:
+		if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Uint8 {
+			return decodeMismatch(mmsType, rv)
+		}
+		rv.SetBytes(toOctetString(mmsValue))
+	case BitString:
+		switch rv.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			rv.SetUint(uint64(C.MmsValue_getBitStringAsInteger(mmsValue)))
+		case reflect.Slice:
+			if rv.Type().Elem().Kind() != reflect.Bool {
+				return decodeMismatch(mmsType, rv)
+			}
+			size := int(C.MmsValue_getBitStringSize(mmsValue))
+			bits := reflect.MakeSlice(rv.Type(), size, size)
+			for i := 0; i < size; i++ {
+				bits.Index(i).SetBool(bool(C.MmsValue_getBitStringBit(mmsValue, C.int(i))))
+			}
+			rv.Set(bits)
+		default:
+			return decodeMismatch(mmsType, rv)
+		}
+	case UTCTime, BinaryTime:
+		if rv.Kind() != reflect.Uint64 {
+			return decodeMismatch(mmsType, rv)
+		}
+		if mmsType == UTCTime {
+			rv.SetUint(uint64(C.MmsValue_getUtcTimeInMs(mmsValue)))
+		} else {
+			rv.SetUint(uint64(C.MmsValue_getBinaryTimeAsUtcMs(mmsValue)))
+		}
+	default:
+		return fmt.Errorf("unsupported type %d", mmsType)
+	}
+	return nil
+}
+
+func decodeMismatch(mmsType MmsType, rv reflect.Value) error {
+	return fmt.Errorf("%w: can not decode %s into %s", TypeInconsistent, mmsType, rv.Type())
+}
+
+func encodeMmsValue(spec *C.MmsVariableSpecification, rv reflect.Value) (*C.MmsValue, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, UserProvidedInvalidArgument
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Type() {
+	case mmsValueType, qualityType, bitStringValueType, timestampType, timeType:
+		return toMmsValueBySpec(spec, rv.Interface())
+	}
+
+	switch mmsType := MmsType(C.MmsVariableSpecification_getType(spec)); mmsType {
+	case Structure:
+		if rv.Kind() != reflect.Struct {
+			return toMmsValueBySpec(spec, rv.Interface())
+		}
+		fields, err := structFields(spec, rv)
+		if err != nil {
+			return nil, err
+		}
+		mmsValue := C.MmsValue_createEmptyStructure(C.int(len(fields)))
+		for i, field := range fields {
+			childSpec := C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))
+			child, err := encodeMmsValue(childSpec, field)
+			if err != nil {
+				C.MmsValue_delete(mmsValue)
+				return nil, fmt.Errorf("%s: %w", C.GoString(C.MmsVariableSpecification_getName(childSpec)), err)
+			}
+			C.MmsValue_setElement(mmsValue, C.int(i), child)
+		}
+		return mmsValue, nil
+	case Array:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%w: can not encode %s as %s", TypeInconsistent, rv.Type(), mmsType)
+		}
+		size := int(C.MmsVariableSpecification_getSize(spec))
+		if rv.Len() != size {
+			return nil, fmt.Errorf("%w: expected %d elements, got %d", ElementCountMismatch, size, rv.Len())
+		}
+		elementSpec := C.MmsVariableSpecification_getArrayElementSpecification(spec)
+		mmsValue := C.MmsValue_createEmptyArray(C.int(size))
+		for i := 0; i < size; i++ {
+			child, err := encodeMmsValue(elementSpec, rv.Index(i))
+			if err != nil {
+				C.MmsValue_delete(mmsValue)
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			C.MmsValue_setElement(mmsValue, C.int(i), child)
+		}
+		return mmsValue, nil
+	}
+
+	// 枚举等自定义类型先转换为基础类型
+	switch rv.Kind() {
+	case reflect.Bool:
+		return toMmsValueBySpec(spec, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return toMmsValueBySpec(spec, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return toMmsValueBySpec(spec, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return toMmsValueBySpec(spec, rv.Float())
+	case reflect.String:
+		return toMmsValueBySpec(spec, rv.String())
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return toMmsValueBySpec(spec, rv.Bytes())
+		}
+	}
+	return toMmsValueBySpec(spec, rv.Interface())
+}
@@ -0,0 +1,209 @@
+package iec61850
+
+/*
+#include <iec61850_client.h>
+
+extern void connectionStateChangedBridge(void* parameter, IedConnection connection, IedConnectionState newState);
+*/
+import "C"
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
+var connectionStateCallbacks sync.Map
+
+// ConnectionState 连接状态
+type ConnectionState int
+
+const (
+	IED_STATE_CLOSED ConnectionState = iota
+	IED_STATE_CONNECTING
+	IED_STATE_CONNECTED
+	IED_STATE_CLOSING
+)
+
+func (state ConnectionState) String() string {
+	switch state {
+	case IED_STATE_CLOSED:
+		return "closed"
+	case IED_STATE_CONNECTING:
+		return "connecting"
+	case IED_STATE_CONNECTED:
+		return "connected"
+	case IED_STATE_CLOSING:
+		return "closing"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateHandler 连接状态变化回调，在 libiec61850 的线程中执行，不应长时间阻塞
+type ConnectionStateHandler func(state ConnectionState)
+
+// ReconnectSettings 自动重连配置，MinBackoff、MaxBackoff 和 Multiplier 小于等于 0 时使用 NewReconnectSettings 的默认值
+type ReconnectSettings struct {
+	MinBackoff   time.Duration          // 首次重连前的等待时间
+	MaxBackoff   time.Duration          // 重连等待时间上限
+	Multiplier   float64                // 每次重连失败后等待时间的增长倍数
+	StateHandler ConnectionStateHandler // 连接状态变化回调，可为空
+}
+
+func NewReconnectSettings() ReconnectSettings {
+	return ReconnectSettings{
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		Multiplier: 2,
+	}
+}
+
+// withDefaults 补全未配置的退避参数，避免 MinBackoff 为 0 时连接失败后立即重连
+func (r ReconnectSettings) withDefaults() ReconnectSettings {
+	defaults := NewReconnectSettings()
+	if r.MinBackoff <= 0 {
+		r.MinBackoff = defaults.MinBackoff
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = defaults.MaxBackoff
+	}
+	if r.Multiplier <= 0 {
+		r.Multiplier = defaults.Multiplier
+	}
+	return r
+}
+
+type clientSupervisor struct {
+	reconnect ReconnectSettings
+	lost      chan struct{}
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+//export connectionStateChangedBridge
+func connectionStateChangedBridge(parameter unsafe.Pointer, _ C.IedConnection, newState C.IedConnectionState) {
+	callbackId := int32(uintptr(parameter))
+	if val, ok := connectionStateCallbacks.Load(callbackId); ok {
+		if client, ok := val.(*Client); ok {
+			client.onStateChanged(ConnectionState(newState))
+		}
+	}
+}
+
+// NewSupervisedClient 创建自动重连的客户端实例，连接在后台建立，断线后按退避策略重连
+func NewSupervisedClient(settings Settings, reconnect ReconnectSettings) (*Client, error) {
+	return newSupervisedClient(settings, reconnect, nil)
+}
+
+func NewSupervisedClientWithTlsSupport(settings Settings, reconnect ReconnectSettings, tlsConfig *TLSConfig) (*Client, error) {
+	return newSupervisedClient(settings, reconnect, tlsConfig)
+}
+
+func newSupervisedClient(settings Settings, reconnect ReconnectSettings, tlsConfig *TLSConfig) (*Client, error) {
+	client, err := createClient(settings, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetConnectionStateHandler(reconnect.StateHandler)
+	client.supervisor = &clientSupervisor{
+		reconnect: reconnect.withDefaults(),
+		lost:      make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go client.supervise()
+	return client, nil
+}
+
+// SetConnectionStateHandler 设置连接状态变化回调
+func (c *Client) SetConnectionStateHandler(handler ConnectionStateHandler) {
+	if handler == nil {
+		c.stateHandler.Store(nil)
+		return
+	}
+	c.stateHandler.Store(&handler)
+}
+
+// GetState 获取当前连接状态，客户端关闭后返回 IED_STATE_CLOSED
+func (c *Client) GetState() ConnectionState {
+	if c.acquire() != nil {
+		return IED_STATE_CLOSED
+	}
+	defer c.release()
+	return ConnectionState(C.IedConnection_getState(c.conn))
+}
+
+func (c *Client) installStateChangedHandler() {
+	c.stateCallbackId = callbackIdGen.Add(1)
+	connectionStateCallbacks.Store(c.stateCallbackId, c)
+
+	// intToPointerBug58625 must be inlined at the C call: storing the fake unsafe.Pointer in a local would let Go 1.26's stack scanner reject it.
+	C.IedConnection_installStateChangedHandler(c.conn, (*[0]byte)(C.connectionStateChangedBridge), intToPointerBug58625(c.stateCallbackId))
+}
+
+func (c *Client) onStateChanged(state ConnectionState) {
+	previous := ConnectionState(c.state.Swap(int32(state)))
+
+	// 回调可能在接收线程中执行，这里只记录状态和通知监督协程，不能发起阻塞的 MMS 请求
+	if c.supervisor != nil && state == IED_STATE_CLOSED && (previous == IED_STATE_CONNECTED || previous == IED_STATE_CLOSING) {
+		select {
+		case c.supervisor.lost <- struct{}{}:
+		default:
+		}
+	}
+
+	if handler := c.stateHandler.Load(); handler != nil {
+		(*handler)(state)
+	}
+}
+
+// supervise 建立连接并在断线后按退避策略重连，直到客户端关闭
+func (c *Client) supervise() {
+	s := c.supervisor
+	defer close(s.done)
+
+	backoff := s.reconnect.MinBackoff
+	for {
+		// 丢弃上一次连接遗留的断线通知
+		select {
+		case <-s.lost:
+		default:
+		}
+
+		if err := c.connect(); err == nil {
+			backoff = s.reconnect.MinBackoff
+			// 连接已可用，恢复报告回调并重新同步缓存报告
+			c.reinstallReportHandlers()
+			c.resyncBufferedReports()
+			select {
+			case <-s.lost:
+			case <-s.quit:
+				return
+			}
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.quit:
+			return
+		}
+		backoff = s.nextBackoff(backoff)
+	}
+}
+
+func (s *clientSupervisor) nextBackoff(backoff time.Duration) time.Duration {
+	if s.reconnect.Multiplier > 1 {
+		backoff = time.Duration(float64(backoff) * s.reconnect.Multiplier)
+	}
+	if s.reconnect.MaxBackoff > 0 && backoff > s.reconnect.MaxBackoff {
+		backoff = s.reconnect.MaxBackoff
+	}
+	return backoff
+}
+
+// stop 停止重连并等待监督协程退出
+func (s *clientSupervisor) stop() {
+	close(s.quit)
+	<-s.done
+}
@@ -0,0 +1,336 @@
+package iec61850
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// UpdateSource 数据更新的来源
+type UpdateSource int
+
+const (
+	UPDATE_SOURCE_REPORT UpdateSource = iota // 报告
+	UPDATE_SOURCE_POLL                       // 轮询
+)
+
+// DataUpdate 数据更新事件，报告和轮询使用同一事件类型
+type DataUpdate struct {
+	ObjectRef  string             // 对象引用，报告中不包含数据引用时为空
+	Value      *MmsValue          // 数据值
+	HasQuality bool               // 是否包含品质
+	Quality    Quality            // 品质
+	Reason     ReasonForInclusion // 上送原因
+	Timestamp  time.Time          // 报告时标或轮询读取的时间
+	Source     UpdateSource       // 来源
+}
+
+// Updates 将报告拆分为数据更新事件
+func (r Report) Updates() []DataUpdate {
+	timestamp := r.Timestamp
+	if !r.HasTimestamp {
+		timestamp = time.Now()
+	}
+
+	updates := make([]DataUpdate, 0, len(r.Members))
+	for _, member := range r.Members {
+		updates = append(updates, DataUpdate{
+			ObjectRef: member.DataReference,
+			Value:     member.Value,
+			Reason:    member.ReasonCode,
+			Timestamp: timestamp,
+			Source:    UPDATE_SOURCE_REPORT,
+		})
+	}
+	return updates
+}
+
+// WatchPoint 轮询点
+type WatchPoint struct {
+	ObjectRef string        // 对象引用，如 simpleIOGenericIO/GGIO1.AnIn1.mag.f
+	FC        FC            // 功能约束
+	Period    time.Duration // 轮询周期，为 0 时使用 WatcherSettings.DefaultPeriod
+	// QualityRef 品质属性引用，如 simpleIOGenericIO/GGIO1.AnIn1.q，与值一起读取，为空时不检测品质变化
+	QualityRef string
+	// AbsoluteDeadband 绝对死区，数值变化不超过该值时不上送
+	AbsoluteDeadband float64
+	// PercentDeadband 百分比死区，数值变化不超过上次上送值的该百分比时不上送
+	PercentDeadband float64
+}
+
+// WatcherSettings 轮询配置
+type WatcherSettings struct {
+	DefaultPeriod time.Duration   // 默认轮询周期
+	BufferSize    int             // 事件通道缓冲区大小
+	ErrorHandler  func(err error) // 读取失败时的回调，可为空
+}
+
+func NewWatcherSettings() WatcherSettings {
+	return WatcherSettings{
+		DefaultPeriod: time.Second,
+		BufferSize:    64,
+	}
+}
+
+// Watcher 周期读取一组属性，只在值超出死区或品质变化时发出事件
+type Watcher struct {
+	client   *Client
+	settings WatcherSettings
+	updates  chan DataUpdate
+
+	mu     sync.Mutex
+	points map[string]*watchedPoint
+	wake   chan struct{}
+	quit   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+type watchedPoint struct {
+	WatchPoint
+	next        time.Time
+	reported    bool
+	lastValue   *MmsValue
+	lastQuality Quality
+}
+
+// NewWatcher 创建轮询器并开始轮询，Stop 后事件通道关闭
+func NewWatcher(client *Client, settings WatcherSettings) *Watcher {
+	if settings.DefaultPeriod <= 0 {
+		settings.DefaultPeriod = time.Second
+	}
+	w := &Watcher{
+		client:   client,
+		settings: settings,
+		updates:  make(chan DataUpdate, settings.BufferSize),
+		points:   make(map[string]*watchedPoint),
+		wake:     make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Updates 返回事件通道
+func (w *Watcher) Updates() <-chan DataUpdate {
+	return w.updates
+}
+
+// Add 添加或替换轮询点，下一轮立即读取
+func (w *Watcher) Add(point WatchPoint) {
+	if point.Period <= 0 {
+		point.Period = w.settings.DefaultPeriod
+	}
+
+	w.mu.Lock()
+	w.points[point.ObjectRef] = &watchedPoint{WatchPoint: point}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Remove 删除轮询点
+func (w *Watcher) Remove(objectRef string) {
+	w.mu.Lock()
+	delete(w.points, objectRef)
+	w.mu.Unlock()
+}
+
+// Stop 停止轮询并关闭事件通道
+func (w *Watcher) Stop() {
+	w.once.Do(func() {
+		close(w.quit)
+		<-w.done
+		close(w.updates)
+	})
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-w.wake:
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		due := w.duePoints(now)
+		if len(due) > 0 {
+			w.poll(due, now)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(w.nextDelay())
+	}
+}
+
+// duePoints 返回到期的轮询点并安排下一次读取
+func (w *Watcher) duePoints(now time.Time) []*watchedPoint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	due := make([]*watchedPoint, 0)
+	for _, point := range w.points {
+		if !point.next.After(now) {
+			due = append(due, point)
+			point.next = now.Add(point.Period)
+		}
+	}
+	return due
+}
+
+func (w *Watcher) nextDelay() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delay := w.settings.DefaultPeriod
+	now := time.Now()
+	for _, point := range w.points {
+		if d := point.next.Sub(now); d < delay {
+			delay = d
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// poll 将到期的点合并为一次 ReadMany
+func (w *Watcher) poll(due []*watchedPoint, now time.Time) {
+	refs := make([]ObjectRefFC, 0, len(due)*2)
+	for _, point := range due {
+		refs = append(refs, ObjectRefFC{ObjectRef: point.ObjectRef, FC: point.FC})
+		if point.QualityRef != "" {
+			refs = append(refs, ObjectRefFC{ObjectRef: point.QualityRef, FC: point.FC})
+		}
+	}
+
+	results, err := w.client.ReadMany(refs)
+	if err != nil {
+		w.handleError(err)
+		return
+	}
+
+	i := 0
+	for _, point := range due {
+		value := results[i]
+		i++
+
+		update := DataUpdate{
+			ObjectRef: point.ObjectRef,
+			Value:     value.Value,
+			Timestamp: now,
+			Source:    UPDATE_SOURCE_POLL,
+		}
+		if point.QualityRef != "" {
+			quality := results[i]
+			i++
+			if quality.Err != nil {
+				w.handleError(quality.Err)
+				continue
+			}
+			q, ok := quality.Value.Value.(uint32)
+			if !ok {
+				w.handleError(UnexpectedValueReceived)
+				continue
+			}
+			update.HasQuality = true
+			update.Quality = Quality(q)
+		}
+		if value.Err != nil {
+			w.handleError(value.Err)
+			continue
+		}
+
+		if update.Reason = w.changed(point, update); update.Reason == IEC61850_REASON_NOT_INCLUDED {
+			continue
+		}
+		select {
+		case w.updates <- update:
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// changed 判断是否需要上送，返回上送原因，不需要上送时返回 IEC61850_REASON_NOT_INCLUDED
+func (w *Watcher) changed(point *watchedPoint, update DataUpdate) ReasonForInclusion {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var reason ReasonForInclusion
+	if !point.reported {
+		reason = IEC61850_REASON_INTEGRITY
+	} else {
+		if update.HasQuality && update.Quality != point.lastQuality {
+			reason |= IEC61850_REASON_QUALITY_CHANGE
+		}
+		if exceedsDeadband(point.WatchPoint, point.lastValue, update.Value) {
+			reason |= IEC61850_REASON_DATA_CHANGE
+		}
+	}
+
+	if reason != IEC61850_REASON_NOT_INCLUDED {
+		point.reported = true
+		point.lastValue = update.Value
+		point.lastQuality = update.Quality
+	}
+	return reason
+}
+
+// exceedsDeadband 数值按死区比较，其他类型有任何变化即上送
+func exceedsDeadband(point WatchPoint, last, current *MmsValue) bool {
+	lastNumber, ok1 := toFloat64(last.Value)
+	currentNumber, ok2 := toFloat64(current.Value)
+	if !ok1 || !ok2 {
+		return !reflect.DeepEqual(last.Value, current.Value)
+	}
+
+	delta := math.Abs(currentNumber - lastNumber)
+	if delta == 0 {
+		return false
+	}
+	if point.AbsoluteDeadband > 0 && delta <= point.AbsoluteDeadband {
+		return false
+	}
+	if point.PercentDeadband > 0 && delta <= math.Abs(lastNumber)*point.PercentDeadband/100 {
+		return false
+	}
+	return true
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func (w *Watcher) handleError(err error) {
+	if w.settings.ErrorHandler != nil {
+		w.settings.ErrorHandler(err)
+	}
+}
@@ -0,0 +1,167 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wendy512/iec61850"
+)
+
+var (
+	host   string
+	port   int
+	dryRun bool
+)
+
+func New() *cobra.Command {
+	rootCommand := &cobra.Command{
+		Use:   "settingstool",
+		Short: "settingstool is used to export, diff and apply protection setting groups of an IED",
+	}
+	rootCommand.PersistentFlags().StringVar(&host, "host", "localhost", "IED host")
+	rootCommand.PersistentFlags().IntVar(&port, "port", 102, "IED MMS port")
+
+	exportCommand := &cobra.Command{
+		Use:   "export <LD> <Output file>",
+		Short: "Export all setting groups of a logical device to a JSON or CSV file",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSettingsExport,
+	}
+
+	diffCommand := &cobra.Command{
+		Use:   "diff <Settings file> [<Settings file>]",
+		Short: "Diff two settings files, or a settings file against the live IED",
+		Long: "Diff two settings files, or a settings file against the live IED.\n\n" +
+			"Diffing against the live IED reads every setting group by selecting it for editing, " +
+			"so it reserves the edit session of the logical device while it runs and releases it afterwards. " +
+			"Other clients can not edit settings of that logical device in the meantime.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runSettingsDiff,
+	}
+
+	applyCommand := &cobra.Command{
+		Use:   "apply <Settings file>",
+		Short: "Apply a settings file to the IED, one edit session per changed setting group",
+		Long: "Apply a settings file to the IED, one edit session per changed setting group.\n\n" +
+			"The live settings are read by selecting every setting group for editing, even with --dry-run, " +
+			"so the edit session of the logical device is reserved while the command runs.",
+		Args: cobra.ExactArgs(1),
+		RunE: runSettingsApply,
+	}
+	applyCommand.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the changes that would be applied, edit sessions are still taken to read the live settings")
+
+	rootCommand.AddCommand(exportCommand, diffCommand, applyCommand)
+	return rootCommand
+}
+
+func runSettingsExport(cmd *cobra.Command, args []string) error {
+	client, err := connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	groups, err := client.ExportSettingGroups(args[0])
+	if err != nil {
+		return err
+	}
+	return writeSettingsFile(args[1], groups)
+}
+
+func runSettingsDiff(cmd *cobra.Command, args []string) error {
+	from, err := readSettingsFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	var to *iec61850.SettingGroups
+	if len(args) == 2 {
+		if to, err = readSettingsFile(args[1]); err != nil {
+			return err
+		}
+	} else {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if to, err = client.ExportSettingGroups(from.LD); err != nil {
+			return err
+		}
+	}
+
+	printSettingDiffs(cmd, iec61850.DiffSettingGroups(from, to))
+	return nil
+}
+
+func runSettingsApply(cmd *cobra.Command, args []string) error {
+	groups, err := readSettingsFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	diffs, err := client.ApplySettingGroups(groups, dryRun)
+	printSettingDiffs(cmd, diffs)
+	return err
+}
+
+func connect() (*iec61850.Client, error) {
+	settings := iec61850.NewSettings()
+	settings.Host = host
+	settings.Port = port
+	return iec61850.NewClient(settings)
+}
+
+func printSettingDiffs(cmd *cobra.Command, diffs []iec61850.SettingDiff) {
+	for _, diff := range diffs {
+		fmt.Fprintln(cmd.OutOrStdout(), diff.String())
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%d difference(s)\n", len(diffs))
+}
+
+func isCSV(fileName string) bool {
+	return strings.EqualFold(filepath.Ext(fileName), ".csv")
+}
+
+func readSettingsFile(fileName string) (*iec61850.SettingGroups, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if isCSV(fileName) {
+		return iec61850.ReadSettingGroupsCSV(file)
+	}
+	return iec61850.ReadSettingGroupsJSON(file)
+}
+
+func writeSettingsFile(fileName string, groups *iec61850.SettingGroups) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if isCSV(fileName) {
+		err = groups.WriteCSV(file)
+	} else {
+		err = groups.WriteJSON(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", fileName, err)
+	}
+	return nil
+}
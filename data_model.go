@@ -1,45 +1,74 @@
 package iec61850
 
+// DataModel 从服务端读取的数据模型
 type DataModel struct {
-	LDs []LD
+	LDs []LD `json:"lds"`
 }
 
 type LD struct {
-	Data string
-	LNs  []LN
+	Data string `json:"name"`
+	LNs  []LN   `json:"lns"`
 }
 
 type LN struct {
-	Data      string
-	DOs       []DO
-	DSs       []DS
-	URReports []URReport
-	BRReports []BRReport
+	Data      string     `json:"name"`
+	DOs       []DO       `json:"dos"`
+	DSs       []DS       `json:"dataSets,omitempty"`
+	URReports []URReport `json:"urcbs,omitempty"`
+	BRReports []BRReport `json:"brcbs,omitempty"`
+	LCBs      []LCB      `json:"lcbs,omitempty"`
+	Logs      []Log      `json:"logs,omitempty"`
+	GoCBs     []GoCB     `json:"gocbs,omitempty"`
+	SGCBs     []SGCB     `json:"sgcbs,omitempty"`
 }
 
 type URReport struct {
-	Data string
+	Data string `json:"name"`
 }
 
 type BRReport struct {
-	Data string
+	Data string `json:"name"`
+}
+
+// LCB 日志控制块
+type LCB struct {
+	Data string `json:"name"`
+}
+
+// Log 日志
+type Log struct {
+	Data string `json:"name"`
+}
+
+// GoCB GOOSE 控制块
+type GoCB struct {
+	Data string `json:"name"`
+}
+
+// SGCB 定值组控制块
+type SGCB struct {
+	Data string `json:"name"`
 }
 
 type DS struct {
-	Data   string
-	DSRefs []DSRef
+	Data      string  `json:"name"`
+	Deletable bool    `json:"deletable"`
+	DSRefs    []DSRef `json:"members"`
 }
 
 type DSRef struct {
-	Data string
+	Data string `json:"reference"`
 }
 
 type DO struct {
-	Data string
-	DAs  []DA
+	Data string `json:"name"`
+	DAs  []DA   `json:"das"`
+	DOs  []DO   `json:"sdos,omitempty"` // 不带功能约束返回的子数据对象
 }
 
 type DA struct {
-	Data string
-	DAs  []DA
+	Data string  `json:"name"`
+	FC   FC      `json:"fc"`   // 功能约束，子属性与所属属性相同
+	Type MmsType `json:"type"` // MMS 类型
+	DAs  []DA    `json:"das,omitempty"`
 }
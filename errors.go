@@ -2,7 +2,11 @@ package iec61850
 
 // #include <iec61850_client.h>
 import "C"
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	NotConnected                      = errors.New("the service request can not be executed because the client is not yet connected")
@@ -36,10 +40,70 @@ var (
 	CreateControlObjectClientFail     = errors.New("control object not found in server")
 	ControlObjectFail                 = errors.New("control object fail")
 	ControlSelectFail                 = errors.New("select control fail")
+	ControlCancelFail                 = errors.New("cancel control fail")
+	ControlTerminationFail            = errors.New("command terminated negatively")
 	UnSupportedOperation              = errors.New("unsupported operation")
 	ReadDataAccessError               = errors.New("data access error")
+	ReadOnlyGoCBElement               = errors.New("the GoCB element is read-only and can not be written by the client")
+	ReadOnlyRCBElement                = errors.New("the RCB element is read-only and can not be written by the client")
+	UnsupportedRCBElement             = errors.New("the RCB element is not available for this kind of report control block")
+	ValueTooLong                      = errors.New("the value exceeds the size given by the variable specification")
+	ElementCountMismatch              = errors.New("the number of elements does not match the variable specification")
+	StructureComponentMissing         = errors.New("the structure component is missing")
+	StructureComponentUnmapped        = errors.New("the structure component has no corresponding tagged field")
+	ReportHandlerExists               = errors.New("the report control block already has a report handler or buffered report subscription")
 )
 
+// GetMmsError 将 MMS 层错误转换为与 GetIedClientError 相同的错误
+func GetMmsError(err C.MmsError) error {
+	switch err {
+	case C.MMS_ERROR_NONE:
+		return nil
+	case C.MMS_ERROR_CONNECTION_REJECTED:
+		return ConnectionRejected
+	case C.MMS_ERROR_CONNECTION_LOST:
+		return ConnectionLost
+	case C.MMS_ERROR_SERVICE_TIMEOUT:
+		return Timeout
+	case C.MMS_ERROR_PARSING_RESPONSE:
+		return MalformedMessage
+	case C.MMS_ERROR_HARDWARE_FAULT:
+		return HardwareFault
+	case C.MMS_ERROR_INVALID_ARGUMENTS:
+		return UserProvidedInvalidArgument
+	case C.MMS_ERROR_OUTSTANDING_CALL_LIMIT:
+		return OutstandingCallLimitReached
+	case C.MMS_ERROR_DEFINITION_INVALID_ADDRESS:
+		return InvalidAddress
+	case C.MMS_ERROR_DEFINITION_TYPE_UNSUPPORTED:
+		return TypeUnsupported
+	case C.MMS_ERROR_DEFINITION_TYPE_INCONSISTENT:
+		return TypeInconsistent
+	case C.MMS_ERROR_DEFINITION_OBJECT_UNDEFINED:
+		return ObjectUndefined
+	case C.MMS_ERROR_DEFINITION_OBJECT_EXISTS:
+		return ObjectExists
+	case C.MMS_ERROR_DEFINITION_OBJECT_ATTRIBUTE_INCONSISTENT:
+		return ObjectAttributeInconsistent
+	case C.MMS_ERROR_ACCESS_OBJECT_NON_EXISTENT, C.MMS_ERROR_FILE_FILE_NON_EXISTENT:
+		return ObjectDoesNotExist
+	case C.MMS_ERROR_ACCESS_OBJECT_ACCESS_UNSUPPORTED:
+		return ObjectAccessUnsupported
+	case C.MMS_ERROR_ACCESS_OBJECT_ACCESS_DENIED, C.MMS_ERROR_FILE_FILE_ACCESS_DENIED:
+		return AccessDenied
+	case C.MMS_ERROR_ACCESS_OBJECT_INVALIDATED:
+		return ObjectInvalidated
+	case C.MMS_ERROR_ACCESS_OBJECT_VALUE_INVALID:
+		return ObjectValueInvalid
+	case C.MMS_ERROR_ACCESS_TEMPORARILY_UNAVAILABLE:
+		return TemporarilyUnavailable
+	case C.MMS_ERROR_REJECT_UNRECOGNIZED_SERVICE:
+		return ServiceNotSupported
+	default:
+		return Unknown
+	}
+}
+
 func GetIedClientError(err C.IedClientError) error {
 	cError := C.IedClientError(err)
 	switch cError {
@@ -101,3 +165,127 @@ func GetIedClientError(err C.IedClientError) error {
 		return Unknown
 	}
 }
+
+// IedError 客户端服务失败时返回的错误，携带服务名、对象引用、功能约束和错误码。
+// errors.Is 可以与上面的错误变量比较，控制服务还可以与 ControlSelectFail 等比较
+type IedError struct {
+	Op            string         // 服务名，如 read、write、operate
+	ObjectRef     string         // 对象引用，没有时为空
+	FC            FC             // 功能约束，没有时为 NONE
+	Code          int            // libiec61850 的 IedClientError 错误码
+	LastApplError *LastApplError // 控制服务失败时服务端返回的 LastApplError
+	Err           error          // 控制服务的失败类型，或直接访问 MMS 层时的 MMS 错误，其他服务为 nil
+}
+
+// newIedError 错误码为 IED_ERROR_OK 时返回 nil
+func newIedError(op, objectRef string, fc FC, code C.IedClientError) error {
+	if code == C.IED_ERROR_OK {
+		return nil
+	}
+	return &IedError{Op: op, ObjectRef: objectRef, FC: fc, Code: int(code)}
+}
+
+// newMmsIedError MMS 层服务失败时返回 Err 为 MMS 错误的 IedError，错误码为 MMS_ERROR_NONE 时返回 nil
+func newMmsIedError(op, objectRef string, fc FC, mmsError C.MmsError) error {
+	err := GetMmsError(mmsError)
+	if err == nil {
+		return nil
+	}
+	return &IedError{Op: op, ObjectRef: objectRef, FC: fc, Err: err}
+}
+
+func (e *IedError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Op)
+	if e.ObjectRef != "" {
+		b.WriteString(" " + e.ObjectRef)
+		if e.FC != NONE {
+			b.WriteString("[" + e.FC.String() + "]")
+		}
+	}
+
+	messages := make([]string, 0, 2)
+	for _, err := range e.Unwrap() {
+		messages = append(messages, err.Error())
+	}
+	b.WriteString(": " + strings.Join(messages, ": "))
+
+	if e.LastApplError != nil && e.LastApplError.AddCause != ADD_CAUSE_UNKNOWN {
+		b.WriteString(fmt.Sprintf(", add cause %s", e.LastApplError.AddCause))
+	}
+	return b.String()
+}
+
+func (e *IedError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	if err := GetIedClientError(C.IedClientError(e.Code)); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// AddCause 返回控制服务失败的附加原因，不是控制服务时返回 ADD_CAUSE_UNKNOWN
+func (e *IedError) AddCause() ControlAddCause {
+	if e.LastApplError == nil {
+		return ADD_CAUSE_UNKNOWN
+	}
+	return e.LastApplError.AddCause
+}
+
+func (e MmsDataAccessError) Error() string {
+	if name, ok := dataAccessErrorNames[e]; ok {
+		return "data access error: " + name
+	}
+	return fmt.Sprintf("data access error %d", int(e))
+}
+
+// Is 使 MmsDataAccessError 可以与 ReadDataAccessError 以及含义相同的客户端错误比较，成功的结果不与任何错误相等
+func (e MmsDataAccessError) Is(target error) bool {
+	if e == DATA_ACCESS_ERROR_SUCCESS || e == DATA_ACCESS_ERROR_SUCCESS_NO_UPDATE {
+		return false
+	}
+	if target == ReadDataAccessError {
+		return true
+	}
+	if sentinel, ok := dataAccessErrorSentinels[e]; ok {
+		return target == sentinel
+	}
+	return false
+}
+
+var dataAccessErrorNames = map[MmsDataAccessError]string{
+	DATA_ACCESS_ERROR_SUCCESS_NO_UPDATE:             "success-no-update",
+	DATA_ACCESS_ERROR_NO_RESPONSE:                   "no-response",
+	DATA_ACCESS_ERROR_SUCCESS:                       "success",
+	DATA_ACCESS_ERROR_OBJECT_INVALIDATED:            "object-invalidated",
+	DATA_ACCESS_ERROR_HARDWARE_FAULT:                "hardware-fault",
+	DATA_ACCESS_ERROR_TEMPORARILY_UNAVAILABLE:       "temporarily-unavailable",
+	DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED:          "object-access-denied",
+	DATA_ACCESS_ERROR_OBJECT_UNDEFINED:              "object-undefined",
+	DATA_ACCESS_ERROR_INVALID_ADDRESS:               "invalid-address",
+	DATA_ACCESS_ERROR_TYPE_UNSUPPORTED:              "type-unsupported",
+	DATA_ACCESS_ERROR_TYPE_INCONSISTENT:             "type-inconsistent",
+	DATA_ACCESS_ERROR_OBJECT_ATTRIBUTE_INCONSISTENT: "object-attribute-inconsistent",
+	DATA_ACCESS_ERROR_OBJECT_ACCESS_UNSUPPORTED:     "object-access-unsupported",
+	DATA_ACCESS_ERROR_OBJECT_NONE_EXISTENT:          "object-non-existent",
+	DATA_ACCESS_ERROR_OBJECT_VALUE_INVALID:          "object-value-invalid",
+	DATA_ACCESS_ERROR_UNKNOWN:                       "unknown",
+}
+
+var dataAccessErrorSentinels = map[MmsDataAccessError]error{
+	DATA_ACCESS_ERROR_OBJECT_INVALIDATED:            ObjectInvalidated,
+	DATA_ACCESS_ERROR_HARDWARE_FAULT:                HardwareFault,
+	DATA_ACCESS_ERROR_TEMPORARILY_UNAVAILABLE:       TemporarilyUnavailable,
+	DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED:          AccessDenied,
+	DATA_ACCESS_ERROR_OBJECT_UNDEFINED:              ObjectUndefined,
+	DATA_ACCESS_ERROR_INVALID_ADDRESS:               InvalidAddress,
+	DATA_ACCESS_ERROR_TYPE_UNSUPPORTED:              TypeUnsupported,
+	DATA_ACCESS_ERROR_TYPE_INCONSISTENT:             TypeInconsistent,
+	DATA_ACCESS_ERROR_OBJECT_ATTRIBUTE_INCONSISTENT: ObjectAttributeInconsistent,
+	DATA_ACCESS_ERROR_OBJECT_ACCESS_UNSUPPORTED:     ObjectAccessUnsupported,
+	DATA_ACCESS_ERROR_OBJECT_NONE_EXISTENT:          ObjectDoesNotExist,
+	DATA_ACCESS_ERROR_OBJECT_VALUE_INVALID:          ObjectValueInvalid,
+}
@@ -2,6 +2,7 @@ package iec61850
 
 // #include <iec61850_client.h>
 import "C"
+import "unsafe"
 
 type FC int
 
@@ -49,3 +50,23 @@ const (
 	ALL  FC = 99
 	NONE FC = -1
 )
+
+func (fc FC) String() string {
+	return C.GoString(C.FunctionalConstraint_toString(C.FunctionalConstraint(fc)))
+}
+
+func (fc FC) MarshalText() ([]byte, error) {
+	return []byte(fc.String()), nil
+}
+
+func (fc *FC) UnmarshalText(text []byte) error {
+	*fc = toFC(string(text))
+	return nil
+}
+
+// toFC 解析功能约束字符串，如 ST、MX，无法识别时返回 NONE
+func toFC(fc string) FC {
+	cFc := C.CString(fc)
+	defer C.free(unsafe.Pointer(cFc))
+	return FC(C.FunctionalConstraint_fromString(cFc))
+}
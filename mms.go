@@ -1,9 +1,20 @@
 package iec61850
 
-// #include <iec61850_client.h>
+/*
+#include <iec61850_client.h>
+
+// specTypeSize 位串的位数、八位组串的长度、字符串的最大长度或二进制时间的字节数，
+// 这几个成员在联合体中位置相同
+static int specTypeSize(MmsVariableSpecification* spec) {
+	return spec->typeSpec.bitString;
+}
+*/
 import "C"
 import (
 	"fmt"
+	"reflect"
+	"time"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/spf13/cast"
@@ -60,17 +71,358 @@ func toMmsValue(mmsType MmsType, value interface{}) (*C.MmsValue, error) {
 		if err != nil {
 			return nil, err
 		}
-	case Int64:
+	case Int64, Integer:
 		mmsValue, err = toInt64MmsValue(value)
 		if err != nil {
 			return nil, err
 		}
+	case Unsigned:
+		mmsValue, err = toUint32MmsValue(value)
+		if err != nil {
+			return nil, err
+		}
+	case VisibleString:
+		mmsValue, err = toVisibleStringMmsValue(value, 0)
+		if err != nil {
+			return nil, err
+		}
+	case OctetString:
+		mmsValue, err = toOctetStringMmsValue(value, 0)
+		if err != nil {
+			return nil, err
+		}
+	case BitString:
+		mmsValue, err = toBitStringMmsValue(value, 0)
+		if err != nil {
+			return nil, err
+		}
+	case UTCTime:
+		mmsValue, err = toUtcTimeMmsValue(value)
+		if err != nil {
+			return nil, err
+		}
+	case BinaryTime:
+		mmsValue, err = toBinaryTimeMmsValue(value, 6)
+		if err != nil {
+			return nil, err
+		}
+	case Structure, Array:
+		mmsValue, err = toComplexMmsValue(mmsType, value)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, UnSupportedOperation
 	}
 	return mmsValue, nil
 }
 
+// toMmsValueBySpec 按类型规格构造 MmsValue，结构体和数组按规格逐个构造成员，
+// 位串、八位组串和字符串按规格检查长度
+func toMmsValueBySpec(spec *C.MmsVariableSpecification, value interface{}) (*C.MmsValue, error) {
+	switch v := value.(type) {
+	case *MmsValue:
+		value = v.Value
+	case MmsValue:
+		value = v.Value
+	}
+
+	size := int(C.specTypeSize(spec))
+	switch mmsType := MmsType(C.MmsVariableSpecification_getType(spec)); mmsType {
+	case Structure:
+		return toStructureMmsValueBySpec(spec, value)
+	case Array:
+		return toArrayMmsValueBySpec(spec, value)
+	case BitString:
+		return toBitStringMmsValue(value, size)
+	case OctetString:
+		return toOctetStringMmsValue(value, size)
+	case VisibleString:
+		return toVisibleStringMmsValue(value, size)
+	case String:
+		return toUnicodeStringMmsValue(value, size)
+	case BinaryTime:
+		return toBinaryTimeMmsValue(value, size)
+	default:
+		return toMmsValue(toSpecMmsType(spec), value)
+	}
+}
+
+// toStructureMmsValueBySpec 结构体可以按顺序给出成员，也可以用 map[string]interface{} 按成员名给出
+func toStructureMmsValueBySpec(spec *C.MmsVariableSpecification, value interface{}) (*C.MmsValue, error) {
+	size := int(C.MmsVariableSpecification_getSize(spec))
+
+	var elementAt func(i int, name string) (interface{}, error)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) != size {
+			return nil, fmt.Errorf("%w: expected %d components, got %d", ElementCountMismatch, size, len(v))
+		}
+		elementAt = func(_ int, name string) (interface{}, error) {
+			element, ok := v[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", StructureComponentMissing, name)
+			}
+			return element, nil
+		}
+	default:
+		elements, err := toSlice(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(elements) != size {
+			return nil, fmt.Errorf("%w: expected %d components, got %d", ElementCountMismatch, size, len(elements))
+		}
+		elementAt = func(i int, _ string) (interface{}, error) {
+			return elements[i], nil
+		}
+	}
+
+	mmsValue := C.MmsValue_createEmptyStructure(C.int(size))
+	for i := 0; i < size; i++ {
+		childSpec := C.MmsVariableSpecification_getChildSpecificationByIndex(spec, C.int(i))
+		name := C.GoString(C.MmsVariableSpecification_getName(childSpec))
+
+		element, err := elementAt(i, name)
+		if err == nil {
+			var child *C.MmsValue
+			if child, err = toMmsValueBySpec(childSpec, element); err == nil {
+				C.MmsValue_setElement(mmsValue, C.int(i), child)
+				continue
+			}
+		}
+		C.MmsValue_delete(mmsValue)
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return mmsValue, nil
+}
+
+func toArrayMmsValueBySpec(spec *C.MmsVariableSpecification, value interface{}) (*C.MmsValue, error) {
+	elementSpec := C.MmsVariableSpecification_getArrayElementSpecification(spec)
+	return toArrayElementsMmsValue(elementSpec, int(C.MmsVariableSpecification_getSize(spec)), value)
+}
+
+// toArrayElementsMmsValue 按元素规格构造 size 个元素的数组，也用于写入数组的一段元素
+func toArrayElementsMmsValue(elementSpec *C.MmsVariableSpecification, size int, value interface{}) (*C.MmsValue, error) {
+	elements, err := toSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) != size {
+		return nil, fmt.Errorf("%w: expected %d elements, got %d", ElementCountMismatch, size, len(elements))
+	}
+
+	mmsValue := C.MmsValue_createEmptyArray(C.int(size))
+	for i, element := range elements {
+		child, err := toMmsValueBySpec(elementSpec, element)
+		if err != nil {
+			C.MmsValue_delete(mmsValue)
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		C.MmsValue_setElement(mmsValue, C.int(i), child)
+	}
+	return mmsValue, nil
+}
+
+// toComplexMmsValue 没有类型规格时，结构体和数组的成员必须是带类型的 MmsValue
+func toComplexMmsValue(mmsType MmsType, value interface{}) (*C.MmsValue, error) {
+	elements, ok := value.([]*MmsValue)
+	if !ok {
+		return nil, StructureMustBeMmsValue
+	}
+
+	var mmsValue *C.MmsValue
+	if mmsType == Structure {
+		mmsValue = C.MmsValue_createEmptyStructure(C.int(len(elements)))
+	} else {
+		mmsValue = C.MmsValue_createEmptyArray(C.int(len(elements)))
+	}
+	for i, element := range elements {
+		child, err := toMmsValue(element.Type, element.Value)
+		if err != nil {
+			C.MmsValue_delete(mmsValue)
+			return nil, err
+		}
+		C.MmsValue_setElement(mmsValue, C.int(i), child)
+	}
+	return mmsValue, nil
+}
+
+// toSlice 将任意切片或数组转换为 []interface{}
+func toSlice(value interface{}) ([]interface{}, error) {
+	if elements, ok := value.([]interface{}); ok {
+		return elements, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: require slice or array, but got %T", TypeInconsistent, value)
+	}
+	elements := make([]interface{}, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, nil
+}
+
+// BitStringValue 指定位数的位串，Value 的第 0 位对应位串的第 0 位
+type BitStringValue struct {
+	Size  int
+	Value uint32
+}
+
+// toBitStringMmsValue size 为 0 时取值本身的位数
+func toBitStringMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	var (
+		bits    []bool
+		intBits uint32
+		bitSize int
+	)
+	switch v := value.(type) {
+	case BitStringValue:
+		intBits, bitSize = v.Value, v.Size
+	case []bool:
+		bits, bitSize = v, len(v)
+	case Quality:
+		intBits, bitSize = uint32(v), 13
+	default:
+		i, err := cast.ToUint32E(value)
+		if err != nil {
+			return nil, err
+		}
+		intBits, bitSize = i, 32
+	}
+
+	if size < 0 {
+		size = -size
+	}
+	if size == 0 {
+		size = bitSize
+	} else if bitSize > size && (bits != nil || intBits>>uint(size) != 0) {
+		return nil, fmt.Errorf("%w: bit string size %d, got %d bits", ValueTooLong, size, bitSize)
+	}
+
+	mmsValue := C.MmsValue_newBitString(C.int(size))
+	if bits != nil {
+		for i, bit := range bits {
+			C.MmsValue_setBitStringBit(mmsValue, C.int(i), C.bool(bit))
+		}
+	} else {
+		C.MmsValue_setBitStringFromInteger(mmsValue, C.uint32_t(intBits))
+	}
+	return mmsValue, nil
+}
+
+// toOctetStringMmsValue size 为负数时表示最大长度，为 0 时不检查长度
+func toOctetStringMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	v, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: octet string requires []byte, but got %T", TypeInconsistent, value)
+	}
+
+	maxSize := size
+	if maxSize < 0 {
+		maxSize = -maxSize
+	}
+	if maxSize == 0 {
+		maxSize = len(v)
+	}
+	if len(v) > maxSize {
+		return nil, fmt.Errorf("%w: octet string size %d, got %d bytes", ValueTooLong, maxSize, len(v))
+	}
+	// 正数表示定长
+	if size > 0 && len(v) != size {
+		return nil, fmt.Errorf("%w: octet string size %d, got %d bytes", TypeInconsistent, size, len(v))
+	}
+
+	mmsValue := C.MmsValue_newOctetString(C.int(len(v)), C.int(maxSize))
+	if len(v) > 0 {
+		C.MmsValue_setOctetString(mmsValue, (*C.uint8_t)(unsafe.Pointer(&v[0])), C.int(len(v)))
+	}
+	return mmsValue, nil
+}
+
+// toVisibleStringMmsValue VisibleString 只能包含 ASCII 可见字符，size 为最大长度，为 0 时不检查长度
+func toVisibleStringMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	v, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range v {
+		if r < 0x20 || r > 0x7e {
+			return nil, fmt.Errorf("%w: visible string contains invalid character %q", TypeInconsistent, r)
+		}
+	}
+	if size < 0 {
+		size = -size
+	}
+	if size > 0 && len(v) > size {
+		return nil, fmt.Errorf("%w: visible string size %d, got %d characters", ValueTooLong, size, len(v))
+	}
+
+	cString := C.CString(v)
+	defer C.free(unsafe.Pointer(cString))
+	return C.MmsValue_newVisibleString(cString), nil
+}
+
+// toUnicodeStringMmsValue MmsString 按 UTF-8 编码，size 为最大字符数，为 0 时不检查长度
+func toUnicodeStringMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	v, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.ValidString(v) {
+		return nil, fmt.Errorf("%w: unicode string is not valid UTF-8", TypeInconsistent)
+	}
+	if size < 0 {
+		size = -size
+	}
+	if n := utf8.RuneCountInString(v); size > 0 && n > size {
+		return nil, fmt.Errorf("%w: unicode string size %d, got %d characters", ValueTooLong, size, n)
+	}
+	return toStringMmsValue(v)
+}
+
+// toUtcTimeMmsValue 支持 time.Time、Timestamp 以及毫秒时间戳
+func toUtcTimeMmsValue(value interface{}) (*C.MmsValue, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return C.MmsValue_newUtcTimeByMsTime(C.uint64_t(v.UnixMilli())), nil
+	case Timestamp:
+		return toUtcTimeMmsValue(&v)
+	case *Timestamp:
+		// 直接复制 8 字节编码，保留时间品质
+		mmsValue := C.MmsValue_newUtcTime(0)
+		C.MmsValue_setUtcTimeByBuffer(mmsValue, (*C.uint8_t)(unsafe.Pointer(&v.cTimestamp)))
+		return mmsValue, nil
+	default:
+		ms, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newUtcTimeByMsTime(C.uint64_t(ms)), nil
+	}
+}
+
+// toBinaryTimeMmsValue size 为 4 时只有一天中的时间，为 6 时包含日期
+func toBinaryTimeMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	var ms uint64
+	switch v := value.(type) {
+	case time.Time:
+		ms = uint64(v.UnixMilli())
+	default:
+		i, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, err
+		}
+		ms = i
+	}
+
+	mmsValue := C.MmsValue_newBinaryTime(C.bool(size == 4))
+	C.MmsValue_setBinaryTime(mmsValue, C.uint64_t(ms))
+	return mmsValue, nil
+}
+
 func toGoValue(mmsValue *C.MmsValue, mmsType MmsType) (interface{}, error) {
 	var (
 		value interface{}
@@ -106,14 +458,21 @@ func toGoValue(mmsValue *C.MmsValue, mmsType MmsType) (interface{}, error) {
 	case UTCTime:
 		value = uint32(C.MmsValue_toUnixTimestamp(mmsValue))
 	case DataAccessError:
-		errorCode := C.MmsValue_getDataAccessError(mmsValue)
-		return nil, fmt.Errorf("failed to read value (error code: %d)", int(errorCode))
+		return nil, MmsDataAccessError(C.MmsValue_getDataAccessError(mmsValue))
 	default:
 		return nil, fmt.Errorf("unsupported type %d", mmsType)
 	}
 	return value, nil
 }
 
+// toTimestamp 复制 UtcTime 的 8 字节编码，保留时间品质
+func toTimestamp(utcTime *C.MmsValue) Timestamp {
+	var timestamp Timestamp
+	buffer := unsafe.Slice((*byte)(unsafe.Pointer(C.MmsValue_getUtcTimeBuffer(utcTime))), len(timestamp.cTimestamp))
+	copy(timestamp.cTimestamp[:], buffer)
+	return timestamp
+}
+
 func toGoStructure(mmsValue *C.MmsValue, mmsType MmsType) ([]*MmsValue, error) {
 	if !(mmsType == Structure || mmsType == Array) {
 		return nil, fmt.Errorf("require struct or array type value, but got type code is: %d", mmsType)
@@ -3,6 +3,7 @@ package scl
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 )
 
 type SCL struct {
@@ -537,6 +538,31 @@ func (that *Communication) getIpAddressByIedName(iedName, apRef string) string {
 	return ""
 }
 
+// LookupConnectedAP 按 IED 名和访问点名查找 ConnectedAP 及其所在的子网，找不到时返回 nil
+func (that *Communication) LookupConnectedAP(iedName, apName string) (*SubNetwork, *ConnectedAP) {
+	for _, subNetwork := range that.SubNetworks {
+		for _, connectedAP := range subNetwork.ConnectedAP {
+			if connectedAP.IedName == iedName && connectedAP.APName == apName {
+				return subNetwork, connectedAP
+			}
+		}
+	}
+	return nil, nil
+}
+
+// GetParameter 返回指定类型的地址参数，如 IP、OSI-PSEL，不存在时返回空字符串
+func (that *Address) GetParameter(paramType string) string {
+	if that == nil {
+		return ""
+	}
+	for _, p := range that.AddressParameters {
+		if p.Type == paramType {
+			return strings.TrimSpace(p.Value)
+		}
+	}
+	return ""
+}
+
 func (that *TriggerOptions) GetIntValue() int {
 	intValue := 0
 
@@ -0,0 +1,62 @@
+package client_auth
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/wendy512/iec61850"
+)
+
+const (
+	port     = 10102
+	password = "top secret"
+)
+
+func startServer(t *testing.T) *iec61850.IedServer {
+	model, err := iec61850.CreateModelFromConfigFileEx("../server/complexModel.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.SetAuthenticator(func(securityToken *unsafe.Pointer, authParameter *iec61850.AcseAuthenticationParameter, appReference *iec61850.IsoApplicationReference) bool {
+		return authParameter.Mechanism == iec61850.ACSE_AUTH_PASSWORD && string(authParameter.Password) == password
+	})
+	server.Start(port)
+	return server
+}
+
+func TestAcsePassword(t *testing.T) {
+	server := startServer(t)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	settings.AcsePassword = password
+	settings.RemoteApTitle = "1.1.1.999"
+	settings.RemoteAeQualifier = 12
+	settings.RemoteSelectors = &iec61850.IsoSelectors{PSel: []byte{0, 0, 0, 1}, SSel: []byte{0, 1}, TSel: []byte{0, 1}}
+	settings.LocalAddress = "127.0.0.1"
+
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("connect with password error %v\n", err)
+	}
+	client.Close()
+
+	settings.AcsePassword = "wrong"
+	if client, err = iec61850.NewClient(settings); err == nil {
+		client.Close()
+		t.Fatalf("connect with wrong password should be rejected\n")
+	}
+	t.Logf("wrong password: %v", err)
+}
+
+func TestSelectorTooLong(t *testing.T) {
+	settings := iec61850.NewSettings()
+	settings.RemoteSelectors = &iec61850.IsoSelectors{TSel: []byte{1, 2, 3, 4, 5}}
+	if _, err := iec61850.NewClient(settings); err == nil {
+		t.Fatalf("expected error for a 5 byte TSEL\n")
+	}
+}
@@ -0,0 +1,68 @@
+package client_brcb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+)
+
+const brcbRef = "simpleIOGenericIO/LLN0.BR.EventsBRCB01"
+
+func TestFileEntryIDStore(t *testing.T) {
+	store := iec61850.NewFileEntryIDStore(t.TempDir())
+
+	entryID, err := store.Load(brcbRef)
+	if err != nil || entryID != nil {
+		t.Fatalf("expected empty entry id, got %v %v", entryID, err)
+	}
+
+	want := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	if err = store.Save(brcbRef, want); err != nil {
+		t.Fatal(err)
+	}
+	if entryID, err = store.Load(brcbRef); err != nil || !bytes.Equal(entryID, want) {
+		t.Fatalf("expected %x, got %x %v", want, entryID, err)
+	}
+}
+
+func TestSubscribeBufferedReports(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	store := iec61850.NewFileEntryIDStore(t.TempDir())
+	sub, err := client.SubscribeBufferedReports(brcbRef, store, func(report iec61850.ClientReport, gap bool) {
+		t.Logf("report %s seqNum %d gap %v", report.GetRptId(), report.GetSeqNum(), gap)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 订阅的回调不能被其他回调替换
+	if err = client.InstallReportHandler(brcbRef, func(iec61850.ClientReport) {}); !errors.Is(err, iec61850.ReportHandlerExists) {
+		t.Fatalf("expected %v, got %v", iec61850.ReportHandlerExists, err)
+	}
+
+	if err = client.TriggerGIReport(brcbRef); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Second)
+
+	if err = sub.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = sub.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	entryID, err := store.Load(brcbRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(entryID, sub.LastEntryID()) {
+		t.Fatalf("stored entry id %x does not match last entry id %x", entryID, sub.LastEntryID())
+	}
+}
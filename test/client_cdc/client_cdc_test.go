@@ -0,0 +1,31 @@
+package client_cdc
+
+import (
+	"testing"
+
+	"github.com/wendy512/iec61850/test"
+)
+
+func TestReadSPS(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	doRef := "simpleIOGenericIO/GGIO1.Ind1"
+	sps, err := client.ReadSPS(doRef)
+	if err != nil {
+		t.Fatalf("read %s error %v\n", doRef, err)
+	}
+	t.Logf("%s -> stVal %v validity %d t %v", doRef, sps.StVal, sps.Q.GetValidity(), sps.T.GetTime())
+}
+
+func TestReadMV(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	doRef := "simpleIOGenericIO/GGIO1.AnIn1"
+	mv, err := client.ReadMV(doRef)
+	if err != nil {
+		t.Fatalf("read %s error %v\n", doRef, err)
+	}
+	t.Logf("%s -> mag %v validity %d t %v", doRef, mv.Mag, mv.Q.GetValidity(), mv.T.GetTime())
+}
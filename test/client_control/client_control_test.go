@@ -4,6 +4,7 @@ import (
 	"github.com/wendy512/iec61850"
 	"github.com/wendy512/iec61850/test"
 	"testing"
+	"time"
 )
 
 const DefValue = false
@@ -43,3 +44,64 @@ func TestControl(t *testing.T) {
 	}
 	test.DoRead(t, client, objectRef+".stVal", iec61850.ST)
 }
+
+func TestControlObject(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "simpleIOGenericIO/GGIO1.SPCSO2"
+	control, err := client.NewControlObject(objectRef)
+	if err != nil {
+		t.Fatalf("create control object %s error %v\n", objectRef, err)
+	}
+	defer control.Close()
+
+	t.Logf("%s ctlModel %d ctlVal type %s", objectRef, control.ControlModel(), control.CtlValType())
+
+	control.SetOrigin("test", iec61850.ORCAT_STATION_CONTROL)
+	control.SetInterlockCheck(true)
+	if err = control.Select(); err != nil {
+		t.Fatalf("select %s error %v\n", objectRef, err)
+	}
+	if err = control.Cancel(); err != nil {
+		t.Fatalf("cancel %s error %v\n", objectRef, err)
+	}
+
+	if err = control.Select(); err != nil {
+		t.Fatalf("select %s error %v\n", objectRef, err)
+	}
+	if err = control.Operate(true, time.Time{}); err != nil {
+		t.Fatalf("operate %s error %v, add cause %s\n", objectRef, err, iec61850.GetControlAddCause(err))
+	}
+	test.DoRead(t, client, objectRef+".stVal", iec61850.ST)
+
+	// 未选择直接执行应被拒绝
+	if err = control.Operate(false, time.Time{}); err == nil {
+		t.Errorf("operate %s without select should fail\n", objectRef)
+	} else {
+		t.Logf("operate without select: %v, last appl error %+v", err, control.LastApplError())
+	}
+}
+
+func TestCommandTermination(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "simpleIOGenericIO/GGIO1.SPCSO3"
+	control, err := client.NewControlObject(objectRef)
+	if err != nil {
+		t.Fatalf("create control object %s error %v\n", objectRef, err)
+	}
+	defer control.Close()
+
+	control.SetCommandTerminationHandler(func(termination iec61850.CommandTermination) {
+		t.Logf("%s command termination success %v add cause %s", termination.ObjectRef, termination.Success, termination.LastApplError.AddCause)
+	})
+
+	termination, err := control.OperateAndWait(true, time.Time{}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("[direct-with-enhanced-security] %s error %v, add cause %s\n", objectRef, err, iec61850.GetControlAddCause(err))
+	}
+	t.Logf("%s terminated, success %v", objectRef, termination.Success)
+	test.DoRead(t, client, objectRef+".stVal", iec61850.ST)
+}
@@ -0,0 +1,53 @@
+package client_dataset
+
+import (
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+	"testing"
+)
+
+const dataSetRef = "simpleIOGenericIO/LLN0.TestDataSet"
+
+func TestDynamicDataSet(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	members := []string{
+		"simpleIOGenericIO/GGIO1.AnIn1.mag.f[MX]",
+		"simpleIOGenericIO/GGIO1.AnIn2.mag.f[MX]",
+	}
+	if err := client.CreateDataSet(dataSetRef, members); err != nil {
+		t.Fatalf("create data set error %v\n", err)
+	}
+
+	directory, err := client.GetDataSetDirectory(dataSetRef)
+	if err != nil {
+		t.Fatalf("get data set directory error %v\n", err)
+	}
+	t.Logf("data set directory -> %#v", directory)
+
+	values, err := client.ReadDataSet(dataSetRef)
+	if err != nil {
+		t.Fatalf("read data set error %v\n", err)
+	}
+	for _, value := range values {
+		t.Logf("data set value -> %v", value.Value)
+	}
+
+	results, err := client.WriteDataSet(dataSetRef, []*iec61850.MmsValue{
+		{Type: iec61850.Float, Value: 1.0},
+		{Type: iec61850.Float, Value: 2.0},
+	})
+	if err != nil {
+		t.Fatalf("write data set error %v\n", err)
+	}
+	t.Logf("write data set access results -> %v", results)
+
+	deleted, err := client.DeleteDataSet(dataSetRef)
+	if err != nil {
+		t.Fatalf("delete data set error %v\n", err)
+	}
+	if !deleted {
+		t.Fatalf("data set %s not deleted\n", dataSetRef)
+	}
+}
@@ -0,0 +1,45 @@
+package client_file
+
+import (
+	"bytes"
+	"github.com/wendy512/iec61850/test"
+	"strings"
+	"testing"
+)
+
+const uploadFileName = "client_file_test.txt"
+
+func TestGetFileDirectory(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	entries, err := client.GetFileDirectory("")
+	if err != nil {
+		t.Fatalf("get file directory error %v\n", err)
+	}
+	for _, entry := range entries {
+		t.Logf("%s %d %s", entry.FileName, entry.FileSize, entry.LastModified)
+	}
+}
+
+func TestSetGetDeleteFile(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	content := "iec61850 file service"
+	if err := client.SetFile(uploadFileName, strings.NewReader(content)); err != nil {
+		t.Fatalf("set file error %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.GetFile(uploadFileName, &buf); err != nil {
+		t.Fatalf("get file error %v\n", err)
+	}
+	if buf.String() != content {
+		t.Fatalf("expect file content %q, but got %q\n", content, buf.String())
+	}
+
+	if err := client.DeleteFile(uploadFileName); err != nil {
+		t.Fatalf("delete file error %v\n", err)
+	}
+}
@@ -0,0 +1,30 @@
+package client_gocb
+
+import (
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+	"testing"
+)
+
+const goCBRef = "simpleIOGenericIO/LLN0.gcbAnalogValues"
+
+func TestGoCB(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	goCB, err := client.GetGoCBValues(goCBRef)
+	if err != nil {
+		t.Fatalf("get GoCB values error %v\n", err)
+	}
+	t.Logf("write before %s -> %#v", goCBRef, goCB)
+
+	goCB.GoEna = true
+	if err = client.SetGoCBValues(goCBRef, *goCB, iec61850.GOCB_ELEMENT_GO_ENA); err != nil {
+		t.Fatalf("set GoCB values error %v\n", err)
+	}
+
+	if goCB, err = client.GetGoCBValues(goCBRef); err != nil {
+		t.Fatalf("get GoCB values error %v\n", err)
+	}
+	t.Logf("write after %s -> %#v", goCBRef, goCB)
+}
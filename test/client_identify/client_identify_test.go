@@ -0,0 +1,62 @@
+package client_identify
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wendy512/iec61850"
+)
+
+const port = 10103
+
+func TestIdentify(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../server/complexModel.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.SetServerIdentity("wendy512", "iec61850", "1.0.0")
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	identity, err := client.Identify()
+	if err != nil {
+		t.Fatalf("identify error %v\n", err)
+	}
+	if identity.Vendor != "wendy512" || identity.Model != "iec61850" || identity.Revision != "1.0.0" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+
+	devices, err := client.GetServerDirectory()
+	if err != nil {
+		t.Fatalf("get server directory error %v\n", err)
+	}
+	if !slices.Contains(devices, "ied1Inverter") {
+		t.Fatalf("expected ied1Inverter in %v", devices)
+	}
+
+	variables, err := client.GetLogicalDeviceVariables("ied1Inverter")
+	if err != nil {
+		t.Fatalf("get logical device variables error %v\n", err)
+	}
+	if !slices.Contains(variables, "ZINV1$SP$OutVarSet$setMag$f") {
+		t.Fatalf("expected ZINV1$SP$OutVarSet$setMag$f in %v", variables)
+	}
+
+	dataSets, err := client.GetLogicalDeviceDataSets("ied1Inverter")
+	if err != nil {
+		t.Fatalf("get logical device data sets error %v\n", err)
+	}
+	if !slices.Contains(dataSets, "LLN0$dataset1") {
+		t.Fatalf("expected LLN0$dataset1 in %v", dataSets)
+	}
+}
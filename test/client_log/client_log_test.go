@@ -0,0 +1,36 @@
+package client_log
+
+import (
+	"github.com/wendy512/iec61850/test"
+	"testing"
+	"time"
+)
+
+const logRef = "simpleIOGenericIO/LLN0$EventLog"
+
+func TestQueryLogByTime(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	entries, moreFollows, err := client.QueryLogByTime(logRef, time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("query log error %v\n", err)
+	}
+	t.Logf("received %d entries, more follows %v", len(entries), moreFollows)
+}
+
+func TestQueryLog(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	it := client.QueryLog(logRef, time.Now().Add(-24*time.Hour), time.Now())
+	for it.Next() {
+		entry := it.Entry()
+		for _, data := range entry.Data {
+			t.Logf("%x %s %s -> %v (%s)", entry.EntryID, entry.Time, data.DataReference, data.Value.Value, data.ReasonCode.GetValueAsString())
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("query log error %v\n", err)
+	}
+}
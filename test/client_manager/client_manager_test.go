@@ -0,0 +1,114 @@
+package client_manager
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/scl"
+)
+
+const port = 10105
+
+func address(params ...string) *scl.Address {
+	address := &scl.Address{}
+	for i := 0; i+1 < len(params); i += 2 {
+		address.AddressParameters = append(address.AddressParameters, &scl.AddressParameter{Type: params[i], Value: params[i+1]})
+	}
+	return address
+}
+
+func ied(name string) *scl.IED {
+	return &scl.IED{Name: name, AccessPoints: []*scl.AccessPoint{{Name: "S1", Server: &scl.Server{}}}}
+}
+
+// scd 三个 IED 指向同一个测试服务端，IED3 没有 IP 地址
+func scd() *scl.SCL {
+	return &scl.SCL{
+		Communication: &scl.Communication{SubNetworks: []*scl.SubNetwork{{
+			Name: "W1",
+			Type: "8-MMS",
+			ConnectedAP: []*scl.ConnectedAP{
+				{IedName: "IED1", APName: "S1", Address: address("IP", "127.0.0.1", "OSI-AP-Title", "1,1,1,999,1", "OSI-AE-Qualifier", "12", "OSI-PSEL", "00000001", "OSI-SSEL", "0001", "OSI-TSEL", "0001")},
+				{IedName: "IED2", APName: "S1", Address: address("IP", "127.0.0.1")},
+				{IedName: "IED3", APName: "S1"},
+			},
+		}}},
+		IEDs: []*scl.IED{ied("IED1"), ied("IED2"), ied("IED3")},
+	}
+}
+
+func TestIedEndpoints(t *testing.T) {
+	endpoints, err := iec61850.IedEndpoints(scd(), iec61850.NewSettings())
+	if err != nil {
+		t.Fatalf("parse endpoints error %v\n", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %+v", endpoints)
+	}
+
+	settings := endpoints[0].Settings
+	if endpoints[0].IedName != "IED1" || endpoints[0].SubNetwork != "W1" || settings.Host != "127.0.0.1" {
+		t.Fatalf("unexpected endpoint %+v", endpoints[0])
+	}
+	if settings.RemoteApTitle != "1.1.1.999.1" || settings.RemoteAeQualifier != 12 {
+		t.Fatalf("unexpected AP title %s %d", settings.RemoteApTitle, settings.RemoteAeQualifier)
+	}
+	if settings.RemoteSelectors == nil || !bytes.Equal(settings.RemoteSelectors.PSel, []byte{0, 0, 0, 1}) {
+		t.Fatalf("unexpected selectors %+v", settings.RemoteSelectors)
+	}
+	if endpoints[1].Settings.RemoteSelectors != nil {
+		t.Fatalf("IED2 should use default selectors")
+	}
+}
+
+func TestIedManager(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../server/complexModel.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewIedManagerSettings()
+	settings.Settings.Port = port
+	manager, err := iec61850.NewIedManager(scd(), settings)
+	if err != nil {
+		t.Fatalf("create manager error %v\n", err)
+	}
+	defer manager.Close()
+
+	if names := manager.IedNames(); !slices.Equal(names, []string{"IED1", "IED2"}) {
+		t.Fatalf("unexpected IED names %v", names)
+	}
+	if _, ok := manager.Client("IED3"); ok {
+		t.Fatalf("IED3 has no IP address and should not be managed")
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !manager.Health().Healthy() {
+		if time.Now().After(deadline) {
+			t.Fatalf("IEDs not connected: %+v", manager.Health())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	client, ok := manager.Client("IED1")
+	if !ok {
+		t.Fatalf("IED1 not found")
+	}
+	value, err := client.Read("ied1Inverter/ZINV1.OutVarSet.setMag.f", iec61850.SP)
+	if err != nil {
+		t.Fatalf("read error %v\n", err)
+	}
+	t.Logf("IED1 read value -> %v", value)
+
+	settings.IedNames = []string{"IED3"}
+	if _, err = iec61850.NewIedManager(scd(), settings); err == nil {
+		t.Fatalf("expected error for IED3 without IP address")
+	}
+}
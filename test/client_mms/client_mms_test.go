@@ -0,0 +1,87 @@
+package client_mms
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wendy512/iec61850"
+)
+
+const (
+	port     = 10104
+	domainId = "ied1Inverter"
+	itemId   = "ZINV1$SP$OutVarSet$setMag$f"
+)
+
+func startServer(t *testing.T) *iec61850.IedServer {
+	model, err := iec61850.CreateModelFromConfigFileEx("../server/complexModel.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	return server
+}
+
+func TestMmsClient(t *testing.T) {
+	server := startServer(t)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+	mms := client.MmsClient()
+
+	domains, err := mms.GetNameList(iec61850.MMS_OBJECT_CLASS_DOMAIN, "")
+	if err != nil {
+		t.Fatalf("get domain names error %v\n", err)
+	}
+	if !slices.Contains(domains, domainId) {
+		t.Fatalf("expected %s in %v", domainId, domains)
+	}
+
+	variables, err := mms.GetNameList(iec61850.MMS_OBJECT_CLASS_NAMED_VARIABLE, domainId)
+	if err != nil {
+		t.Fatalf("get variable names error %v\n", err)
+	}
+	if !slices.Contains(variables, itemId) {
+		t.Fatalf("expected %s in %v", itemId, variables)
+	}
+
+	lists, err := mms.GetNameList(iec61850.MMS_OBJECT_CLASS_NAMED_VARIABLE_LIST, domainId)
+	if err != nil {
+		t.Fatalf("get variable list names error %v\n", err)
+	}
+	if !slices.Contains(lists, "LLN0$dataset1") {
+		t.Fatalf("expected LLN0$dataset1 in %v", lists)
+	}
+
+	spec, err := mms.GetVariableAccessAttributes(domainId, "ZINV1$SP$OutVarSet$setMag")
+	if err != nil {
+		t.Fatalf("get variable access attributes error %v\n", err)
+	}
+	if spec.Type != iec61850.Structure || len(spec.Components) != 1 || spec.Components[0].Name != "f" || spec.Components[0].Type != iec61850.Float {
+		t.Fatalf("unexpected variable spec %+v", spec)
+	}
+
+	if err = mms.WriteVariable(domainId, itemId, nil, 42); err != nil {
+		t.Fatalf("write %s/%s error %v\n", domainId, itemId, err)
+	}
+	value, err := mms.ReadVariable(domainId, itemId, nil)
+	if err != nil {
+		t.Fatalf("read %s/%s error %v\n", domainId, itemId, err)
+	}
+	if value.Type != iec61850.Float || value.Value != float32(42) {
+		t.Fatalf("expected 42, got %+v", value)
+	}
+
+	// 备选访问必须选择数组下标或结构体成员
+	if _, err = mms.ReadVariable(domainId, itemId, &iec61850.MmsAlternateAccess{Index: -1}); err == nil {
+		t.Fatalf("expected error for empty alternate access")
+	}
+}
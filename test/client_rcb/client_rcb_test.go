@@ -5,6 +5,7 @@ import (
 	"github.com/wendy512/iec61850/test"
 	"log"
 	"testing"
+	"time"
 )
 
 func TestRBC(t *testing.T) {
@@ -58,3 +59,94 @@ func TestRBC(t *testing.T) {
 	}
 	log.Printf("write after %s -> %#v\n", rbcRef, rcbValue)
 }
+
+func TestRCBWithMask(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	brcbRef := "simpleIOGenericIO/LLN0.BR.EventsBRCB01"
+	rcbValue, err := client.GetRCBValues(brcbRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Printf("write before %s -> %#v\n", brcbRef, rcbValue)
+	if !rcbValue.Buffered {
+		t.Fatalf("%s should be buffered", brcbRef)
+	}
+
+	// 先修改数据集和缓存时间，再使能
+	rcbValue.DatSet = "simpleIOGenericIO/LLN0$Events"
+	rcbValue.BufTm = 100
+	if err = client.SetRCBValuesWithMask(brcbRef, *rcbValue, iec61850.RCB_ELEMENT_DATSET|iec61850.RCB_ELEMENT_BUF_TM); err != nil {
+		t.Fatal(err)
+	}
+	rcbValue.Ena = true
+	if err = client.SetRCBValuesWithMask(brcbRef, *rcbValue, iec61850.RCB_ELEMENT_RPT_ENA); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.SetRCBValuesWithMask(brcbRef, *rcbValue, iec61850.RCB_ELEMENT_RESV); err != iec61850.UnsupportedRCBElement {
+		t.Fatalf("expected %v, got %v", iec61850.UnsupportedRCBElement, err)
+	}
+	if err = client.SetRCBValuesWithMask(brcbRef, *rcbValue, iec61850.RCB_ELEMENT_CONF_REV); err != iec61850.ReadOnlyRCBElement {
+		t.Fatalf("expected %v, got %v", iec61850.ReadOnlyRCBElement, err)
+	}
+
+	rcbValue, err = client.GetRCBValues(brcbRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Printf("write after %s -> %#v\n", brcbRef, rcbValue)
+}
+
+func TestSubscribeReports(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	rcbRef := "simpleIOGenericIO/LLN0.RP.EventsRCB01"
+	settings := iec61850.NewReportStreamSettings()
+	settings.BufferSize = 8
+	reports, err := client.SubscribeReports(rcbRef, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.UnsubscribeReports(rcbRef)
+
+	err = client.SetRCBValues(rcbRef, iec61850.ClientReportControlBlock{
+		Ena: true,
+		OptFlds: iec61850.OptFlds{
+			SequenceNumber:     true,
+			ReasonForInclusion: true,
+			DataSetName:        true,
+			DataReference:      true,
+		},
+		TrgOps: iec61850.TrgOps{
+			DataChange: true,
+			Gi:         true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.TriggerGIReport(rcbRef); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case report := <-reports:
+		log.Printf("report %s seqNum %d dataSet %s\n", report.RptID, report.SeqNum, report.DataSetName)
+		for _, member := range report.Members {
+			if member.Err != nil {
+				t.Errorf("decode member %d %s error %v", member.Index, member.DataReference, member.Err)
+				continue
+			}
+			if member.Value == nil {
+				t.Errorf("member %d %s has no value", member.Index, member.DataReference)
+				continue
+			}
+			log.Printf("  %s -> %v (%s)\n", member.DataReference, member.Value, member.ReasonCode.GetValueAsString())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no report received")
+	}
+}
@@ -0,0 +1,64 @@
+package client_rw
+
+import (
+	"context"
+	"errors"
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadContext(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, objectRef := range []string{AnIn1ObjectRef, Ind1ObjectRef} {
+		fc := iec61850.MX
+		if objectRef == Ind1ObjectRef {
+			fc = iec61850.ST
+		}
+
+		wg.Add(1)
+		go func(objectRef string, fc iec61850.FC) {
+			defer wg.Done()
+			value, err := client.ReadContext(ctx, objectRef, fc)
+			if err != nil {
+				t.Errorf("read %s object error %v\n", objectRef, err)
+				return
+			}
+			t.Logf("read %s value -> %v", objectRef, value)
+		}(objectRef, fc)
+	}
+	wg.Wait()
+}
+
+func TestReadContextCanceled(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.ReadContext(ctx, AnIn1ObjectRef, iec61850.MX); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context canceled, but got %v\n", err)
+	}
+}
+
+func TestWriteContextStructure(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objectRef := "ied1Inverter/ZINV1.OutVarSet.setMag"
+	if err := client.WriteContext(ctx, objectRef, iec61850.SP, map[string]interface{}{"f": 70}); err != nil {
+		t.Fatalf("write %s error %v\n", objectRef, err)
+	}
+}
@@ -0,0 +1,114 @@
+package client_rw
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wendy512/iec61850"
+)
+
+const (
+	concurrencyPort = 10106
+	vendorObjectRef = "simpleIOGenericIO/GGIO1.NamPlt.vendor"
+)
+
+// startServer 在本进程中启动服务端，测试不依赖外部服务端
+func startServer(t *testing.T) *iec61850.IedServer {
+	model, err := iec61850.CreateModelFromConfigFileEx("../server/simpleIO_direct_control_goose.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.SetHandleWriteAccess(model.GetModelNodeByObjectReference(vendorObjectRef), func(*iec61850.ModelNode, *iec61850.MmsValue) iec61850.MmsDataAccessError {
+		return iec61850.DATA_ACCESS_ERROR_SUCCESS
+	})
+	server.SetControlHandler(model.GetModelNodeByObjectReference("simpleIOGenericIO/GGIO1.SPCSO1"), func(*iec61850.ModelNode, *iec61850.ControlAction, *iec61850.MmsValue, bool) iec61850.ControlHandlerResult {
+		return iec61850.CONTROL_RESULT_OK
+	})
+	server.Start(concurrencyPort)
+	return server
+}
+
+// TestConcurrentClose 多个 goroutine 并发读写时关闭客户端，CI 中使用 go test -race 运行
+func TestConcurrentClose(t *testing.T) {
+	server := startServer(t)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = concurrencyPort
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+
+	if err = client.InstallReportHandler("simpleIOGenericIO/LLN0.RP.EventsRCB01", func(iec61850.ClientReport) {}); err != nil {
+		t.Fatalf("install report handler error %v\n", err)
+	}
+	control, err := client.NewControlObject("simpleIOGenericIO/GGIO1.SPCSO1")
+	if err != nil {
+		t.Fatalf("create control object error %v\n", err)
+	}
+
+	stop := make(chan struct{})
+	errs := make(chan error, 64)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				var err error
+				switch i % 4 {
+				case 0:
+					_, err = client.Read(AnIn1ObjectRef, iec61850.MX)
+				case 1:
+					_, err = client.ReadBool(Ind1ObjectRef, iec61850.ST)
+				case 2:
+					err = client.Write(vendorObjectRef, iec61850.DC, "vendor")
+				case 3:
+					err = control.Operate(true, time.Time{})
+				}
+				if errors.Is(err, iec61850.NotConnected) {
+					return
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	client.Close()
+	// 重复关闭不会阻塞或释放两次
+	client.Close()
+	close(stop)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error %v\n", err)
+	}
+	if _, err := client.Read(AnIn1ObjectRef, iec61850.MX); !errors.Is(err, iec61850.NotConnected) {
+		t.Fatalf("expected %v after close, got %v", iec61850.NotConnected, err)
+	}
+	if err := control.Operate(true, time.Time{}); !errors.Is(err, iec61850.NotConnected) {
+		t.Fatalf("expected %v after close, got %v", iec61850.NotConnected, err)
+	}
+	if state := client.GetState(); state != iec61850.IED_STATE_CLOSED {
+		t.Fatalf("expected state %v after close, got %v", iec61850.IED_STATE_CLOSED, state)
+	}
+	// 客户端关闭时已释放控制对象
+	control.Close()
+}
@@ -56,3 +56,51 @@ func TestGetLogicalDeviceList(t *testing.T) {
 	}
 	fmt.Println(string(marshal))
 }
+
+func TestGetDataModel(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	dataModel, err := client.GetDataModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshal, err := json.MarshalIndent(dataModel, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(string(marshal))
+
+	das, err := client.GetDataAttributes("simpleIOGenericIO/GGIO1.SPCSO1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(das) == 0 {
+		t.Fatal("expected data attributes of SPCSO1")
+	}
+	for _, da := range das {
+		t.Logf("%s[%s] %s", da.Data, da.FC, da.Type)
+	}
+}
+
+func TestReadMany(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	refs := []iec61850.ObjectRefFC{
+		{ObjectRef: "simpleIOGenericIO/GGIO1.AnIn1.mag.f", FC: iec61850.MX},
+		{ObjectRef: "simpleIOGenericIO/GGIO1.AnIn1.q", FC: iec61850.MX},
+		{ObjectRef: "simpleIOGenericIO/GGIO1.SPCSO1.stVal", FC: iec61850.ST},
+		{ObjectRef: "simpleIOGenericIO/GGIO1.NotExist.stVal", FC: iec61850.ST},
+	}
+	results, err := client.ReadMany(refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		t.Logf("%s -> %v %v", refs[i].ObjectRef, result.Value, result.Err)
+	}
+	if results[3].Err == nil {
+		t.Fatalf("expected data access error for %s", refs[3].ObjectRef)
+	}
+}
@@ -0,0 +1,49 @@
+package client_rw
+
+import (
+	"testing"
+
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+)
+
+type spcStatus struct {
+	StVal  bool               `iec61850:"stVal"`
+	Q      iec61850.Quality   `iec61850:"q"`
+	T      iec61850.Timestamp `iec61850:"t"`
+	StSeld bool               `iec61850:"stSeld,optional"`
+}
+
+type analogueValue struct {
+	F float32 `iec61850:"f"`
+}
+
+func TestReadInto(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "simpleIOGenericIO/GGIO1.SPCSO1"
+	var status spcStatus
+	if err := client.ReadInto(objectRef, iec61850.ST, &status); err != nil {
+		t.Fatalf("read %s error %v\n", objectRef, err)
+	}
+	t.Logf("%s -> stVal %v q %d t %v", objectRef, status.StVal, status.Q, status.T.GetTime())
+}
+
+func TestWriteFrom(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "ied1Inverter/ZINV1.OutVarSet.setMag"
+	if err := client.WriteFrom(objectRef, iec61850.SP, analogueValue{F: 80}); err != nil {
+		t.Fatalf("write %s error %v\n", objectRef, err)
+	}
+
+	var value analogueValue
+	if err := client.ReadInto(objectRef, iec61850.SP, &value); err != nil {
+		t.Fatalf("read %s error %v\n", objectRef, err)
+	}
+	if value.F != 80 {
+		t.Fatalf("expected 80, got %v", value.F)
+	}
+}
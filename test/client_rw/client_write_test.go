@@ -1,6 +1,7 @@
 package client_rw
 
 import (
+	"errors"
 	"github.com/wendy512/iec61850"
 	"github.com/wendy512/iec61850/test"
 	"testing"
@@ -18,3 +19,42 @@ func TestWrite(t *testing.T) {
 		t.Fatalf("write %s error %v\n", OutVarObjectRef, err)
 	}
 }
+
+func TestWriteStructure(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "ied1Inverter/ZINV1.OutVarSet.setMag"
+	if err := client.Write(objectRef, iec61850.SP, map[string]interface{}{"f": 50}); err != nil {
+		t.Fatalf("write %s error %v\n", objectRef, err)
+	}
+	if err := client.Write(objectRef, iec61850.SP, []interface{}{60}); err != nil {
+		t.Fatalf("write %s error %v\n", objectRef, err)
+	}
+	if err := client.Write(objectRef, iec61850.SP, map[string]interface{}{"i": 60}); !errors.Is(err, iec61850.StructureComponentMissing) {
+		t.Fatalf("expected %v, got %v", iec61850.StructureComponentMissing, err)
+	}
+}
+
+func TestIedError(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	objectRef := "ied1Inverter/ZINV1.NotExist.setMag.f"
+	_, err := client.Read(objectRef, iec61850.SP)
+	if err == nil {
+		t.Fatalf("expected error reading %s", objectRef)
+	}
+
+	var iedErr *iec61850.IedError
+	if !errors.As(err, &iedErr) {
+		t.Fatalf("expected *IedError, got %T %v", err, err)
+	}
+	if iedErr.Op != "read" || iedErr.ObjectRef != objectRef || iedErr.FC != iec61850.SP {
+		t.Fatalf("unexpected error context %+v", iedErr)
+	}
+	if !errors.Is(err, iec61850.ObjectDoesNotExist) {
+		t.Fatalf("expected %v, got %v", iec61850.ObjectDoesNotExist, err)
+	}
+	t.Log(err)
+}
@@ -0,0 +1,63 @@
+package client_sg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+)
+
+func TestExportSettingGroups(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	groups, err := client.ExportSettingGroups("DEMOPROT")
+	if err != nil {
+		t.Fatalf("export setting groups error %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	if err = groups.WriteJSON(&buf); err != nil {
+		t.Fatalf("write json error %v\n", err)
+	}
+	t.Logf("%s", buf.String())
+
+	diffs, err := client.ApplySettingGroups(groups, true)
+	if err != nil {
+		t.Fatalf("apply setting groups error %v\n", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no differences against the exported settings, got %v\n", diffs)
+	}
+}
+
+func TestDiffSettingGroups(t *testing.T) {
+	from := &iec61850.SettingGroups{
+		LD: "DEMOPROT",
+		Groups: []iec61850.SettingGroupValues{{SG: 1, Values: []iec61850.SettingValue{
+			{ObjectRef: "DEMOPROT/PTOC1.StrVal.setMag.f", Value: &iec61850.MmsValue{Type: iec61850.Float, Value: float32(0.1)}},
+			{ObjectRef: "DEMOPROT/PTOC1.OpDlTmms.setVal", Value: &iec61850.MmsValue{Type: iec61850.Integer, Value: int32(500)}},
+		}}},
+	}
+
+	// CSV 往返后 float32 变为 float64，不应产生差异
+	var buf bytes.Buffer
+	if err := from.WriteCSV(&buf); err != nil {
+		t.Fatalf("write csv error %v\n", err)
+	}
+	to, err := iec61850.ReadSettingGroupsCSV(&buf)
+	if err != nil {
+		t.Fatalf("read csv error %v\n", err)
+	}
+	if diffs := iec61850.DiffSettingGroups(from, to); len(diffs) != 0 {
+		t.Fatalf("expected no differences after csv round trip, got %v\n", diffs)
+	}
+
+	to.Groups[0].Values[1].Value = &iec61850.MmsValue{Type: iec61850.Integer, Value: 600}
+	diffs := iec61850.DiffSettingGroups(from, to)
+	if len(diffs) != 1 || diffs[0].ObjectRef != "DEMOPROT/PTOC1.OpDlTmms.setVal" {
+		t.Fatalf("unexpected differences %v\n", diffs)
+	}
+	t.Log(diffs[0])
+}
@@ -26,3 +26,51 @@ func TestGetSG(t *testing.T) {
 
 	t.Logf("setting group info %#v\n", sgInfo)
 }
+
+func TestGetSGCBReference(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	sgcb, err := client.GetSGCBReference("DEMOPROT")
+	if err != nil {
+		t.Fatalf("get SGCB reference error %v\n", err)
+	}
+	if sgcb != "DEMOPROT/LLN0.SGCB" {
+		t.Fatalf("expected SGCB DEMOPROT/LLN0.SGCB, got %s", sgcb)
+	}
+}
+
+func TestEditSG(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	sgInfo, err := client.GetSG("DEMOPROT/LLN0.SGCB")
+	if err != nil {
+		t.Fatalf("get setting group error %v\n", err)
+	}
+	t.Logf("active %d LActTm %v has ResvTms %v", sgInfo.ActSG, sgInfo.LActTm, sgInfo.HasResvTms)
+
+	if err = client.SelectEditSG("DEMOPROT", 2); err != nil {
+		t.Fatalf("select edit setting group error %v\n", err)
+	}
+	settings, err := client.ReadEditSG("DEMOPROT")
+	if err != nil {
+		t.Fatalf("read edit setting group error %v\n", err)
+	}
+	for _, setting := range settings {
+		t.Logf("%s = %v", setting.ObjectRef, setting.Value.Value)
+	}
+
+	values := []iec61850.SettingValue{
+		{ObjectRef: "DEMOPROT/PTOC1.StrVal.setMag.f", Value: &iec61850.MmsValue{Type: iec61850.Float, Value: float32(1.5)}},
+	}
+	if err = client.SetEditSGValues("DEMOPROT", values); err != nil {
+		t.Fatalf("set edit setting group values error %v\n", err)
+	}
+	if err = client.ConfirmEditSG("DEMOPROT"); err != nil {
+		t.Fatalf("confirm edit setting group error %v\n", err)
+	}
+	if err = client.SelectActiveSG("DEMOPROT", 2); err != nil {
+		t.Fatalf("select active setting group error %v\n", err)
+	}
+}
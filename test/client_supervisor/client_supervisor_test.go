@@ -0,0 +1,39 @@
+package client_supervisor
+
+import (
+	"github.com/wendy512/iec61850"
+	"testing"
+	"time"
+)
+
+func TestSupervisedClient(t *testing.T) {
+	states := make(chan iec61850.ConnectionState, 16)
+	reconnect := iec61850.NewReconnectSettings()
+	reconnect.StateHandler = func(state iec61850.ConnectionState) {
+		states <- state
+	}
+
+	client, err := iec61850.NewSupervisedClient(iec61850.NewSettings(), reconnect)
+	if err != nil {
+		t.Fatalf("create supervised client error %v\n", err)
+	}
+	defer client.Close()
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			t.Logf("connection state -> %s", state)
+			if state == iec61850.IED_STATE_CONNECTED {
+				value, err := client.Read("simpleIOGenericIO/GGIO1.AnIn1.mag.f", iec61850.MX)
+				if err != nil {
+					t.Fatalf("read error %v\n", err)
+				}
+				t.Logf("read value -> %v", value)
+				return
+			}
+		case <-timeout:
+			t.Fatal("supervised client not connected")
+		}
+	}
+}
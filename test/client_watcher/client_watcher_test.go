@@ -0,0 +1,43 @@
+package client_watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wendy512/iec61850"
+	"github.com/wendy512/iec61850/test"
+)
+
+func TestWatcher(t *testing.T) {
+	client := test.CreateClient(t)
+	defer test.CloseClient(client)
+
+	settings := iec61850.NewWatcherSettings()
+	settings.ErrorHandler = func(err error) {
+		t.Logf("poll error %v", err)
+	}
+	watcher := iec61850.NewWatcher(client, settings)
+	defer watcher.Stop()
+
+	watcher.Add(iec61850.WatchPoint{
+		ObjectRef:        "simpleIOGenericIO/GGIO1.AnIn1.mag.f",
+		FC:               iec61850.MX,
+		Period:           500 * time.Millisecond,
+		QualityRef:       "simpleIOGenericIO/GGIO1.AnIn1.q",
+		AbsoluteDeadband: 0.1,
+	})
+	watcher.Add(iec61850.WatchPoint{
+		ObjectRef: "simpleIOGenericIO/GGIO1.Ind1.stVal",
+		FC:        iec61850.ST,
+	})
+
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case update := <-watcher.Updates():
+			t.Logf("%s -> %v reason %d quality %d", update.ObjectRef, update.Value.Value, update.Reason, update.Quality)
+		case <-timeout:
+			return
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package iec61850
 
+import "fmt"
+
 type MmsType int
 
 type MmsValue struct {
@@ -34,6 +36,53 @@ const (
 	Uint32
 )
 
+var mmsTypeNames = []string{
+	Array:           "Array",
+	Structure:       "Structure",
+	Boolean:         "Boolean",
+	BitString:       "BitString",
+	Integer:         "Integer",
+	Unsigned:        "Unsigned",
+	Float:           "Float",
+	OctetString:     "OctetString",
+	VisibleString:   "VisibleString",
+	GeneralizedTime: "GeneralizedTime",
+	BinaryTime:      "BinaryTime",
+	Bcd:             "Bcd",
+	ObjId:           "ObjId",
+	String:          "String",
+	UTCTime:         "UTCTime",
+	DataAccessError: "DataAccessError",
+	Int8:            "Int8",
+	Int16:           "Int16",
+	Int32:           "Int32",
+	Int64:           "Int64",
+	Uint8:           "Uint8",
+	Uint16:          "Uint16",
+	Uint32:          "Uint32",
+}
+
+func (t MmsType) String() string {
+	if t >= 0 && int(t) < len(mmsTypeNames) {
+		return mmsTypeNames[t]
+	}
+	return fmt.Sprintf("MmsType(%d)", int(t))
+}
+
+func (t MmsType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *MmsType) UnmarshalText(text []byte) error {
+	for i, name := range mmsTypeNames {
+		if name == string(text) {
+			*t = MmsType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown mms type %q", text)
+}
+
 type MmsDataAccessError int
 
 const (
@@ -108,6 +157,44 @@ const (
 	ADD_CAUSE_LOCKED_BY_OTHER_CLIENT         ControlAddCause = 27
 )
 
+var controlAddCauseNames = []string{
+	ADD_CAUSE_UNKNOWN:                        "unknown",
+	ADD_CAUSE_NOT_SUPPORTED:                  "not-supported",
+	ADD_CAUSE_BLOCKED_BY_SWITCHING_HIERARCHY: "blocked-by-switching-hierarchy",
+	ADD_CAUSE_SELECT_FAILED:                  "select-failed",
+	ADD_CAUSE_INVALID_POSITION:               "invalid-position",
+	ADD_CAUSE_POSITION_REACHED:               "position-reached",
+	ADD_CAUSE_PARAMETER_CHANGE_IN_EXECUTION:  "parameter-change-in-execution",
+	ADD_CAUSE_STEP_LIMIT:                     "step-limit",
+	ADD_CAUSE_BLOCKED_BY_MODE:                "blocked-by-mode",
+	ADD_CAUSE_BLOCKED_BY_PROCESS:             "blocked-by-process",
+	ADD_CAUSE_BLOCKED_BY_INTERLOCKING:        "blocked-by-interlocking",
+	ADD_CAUSE_BLOCKED_BY_SYNCHROCHECK:        "blocked-by-synchrocheck",
+	ADD_CAUSE_COMMAND_ALREADY_IN_EXECUTION:   "command-already-in-execution",
+	ADD_CAUSE_BLOCKED_BY_HEALTH:              "blocked-by-health",
+	ADD_CAUSE_1_OF_N_CONTROL:                 "1-of-n-control",
+	ADD_CAUSE_ABORTION_BY_CANCEL:             "abortion-by-cancel",
+	ADD_CAUSE_TIME_LIMIT_OVER:                "time-limit-over",
+	ADD_CAUSE_ABORTION_BY_TRIP:               "abortion-by-trip",
+	ADD_CAUSE_OBJECT_NOT_SELECTED:            "object-not-selected",
+	ADD_CAUSE_OBJECT_ALREADY_SELECTED:        "object-already-selected",
+	ADD_CAUSE_NO_ACCESS_AUTHORITY:            "no-access-authority",
+	ADD_CAUSE_ENDED_WITH_OVERSHOOT:           "ended-with-overshoot",
+	ADD_CAUSE_ABORTION_DUE_TO_DEVIATION:      "abortion-due-to-deviation",
+	ADD_CAUSE_ABORTION_BY_COMMUNICATION_LOSS: "abortion-by-communication-loss",
+	ADD_CAUSE_ABORTION_BY_COMMAND:            "abortion-by-command",
+	ADD_CAUSE_NONE:                           "none",
+	ADD_CAUSE_INCONSISTENT_PARAMETERS:        "inconsistent-parameters",
+	ADD_CAUSE_LOCKED_BY_OTHER_CLIENT:         "locked-by-other-client",
+}
+
+func (a ControlAddCause) String() string {
+	if a >= 0 && int(a) < len(controlAddCauseNames) {
+		return controlAddCauseNames[a]
+	}
+	return fmt.Sprintf("ControlAddCause(%d)", int(a))
+}
+
 type ControlModel int
 
 const (